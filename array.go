@@ -0,0 +1,155 @@
+package gclog
+
+import "time"
+
+// Arr is an array being built by Line.Array. Its values may be of different
+// types, objects or other arrays. It is written to the buffer of the line.
+type Arr Line
+
+// ArrayMarshaler is implemented by types which write themselves as an array.
+// MarshalLogArray writes the values of the array to a. Eg:
+//
+//	func (items Items) MarshalLogArray(a *gclog.Arr) {
+//		for _, it := range items {
+//			a.Object(it)
+//		}
+//	}
+type ArrayMarshaler interface {
+	MarshalLogArray(a *Arr)
+}
+
+type ArrayMarshalerFunc func(a *Arr)
+
+func (f ArrayMarshalerFunc) MarshalLogArray(a *Arr) {
+	f(a)
+}
+
+// Array writes an array with the values written by fn. Eg:
+//
+//	log.Info().Array("items", func(a *gclog.Arr) {
+//		for _, it := range items {
+//			a.Dict(func(d *gclog.Line) { d.Str("id", it.ID).Int("qty", it.Qty) })
+//		}
+//	}).Msg("order")
+//
+// fn must not keep the Arr.
+func (l *Line) Array(key string, fn func(a *Arr)) *Line {
+	return l.ArrayOf(key, ArrayMarshalerFunc(fn))
+}
+
+// ArrayOf writes the array. A nil val is written as an empty array.
+func (l *Line) ArrayOf(key string, val ArrayMarshaler) *Line {
+	if l == nil {
+		return l
+	}
+
+	l.appendKey(key)
+	l.appendArray(val)
+	return l
+}
+
+// appendArray writes an array, as a value.
+func (l *Line) appendArray(val ArrayMarshaler) {
+	fieldsStart := l.fieldsStart
+	inArray := l.appendArrayStart()
+	l.fieldsStart = len(l.buff)
+	if val != nil {
+		val.MarshalLogArray((*Arr)(l))
+	}
+	l.appendArrayEnd(inArray)
+	l.fieldsStart = fieldsStart
+}
+
+// elem writes the delimiter (if it is not the first value) and returns the line of the array.
+func (a *Arr) elem() *Line {
+	l := (*Line)(a)
+	if len(l.buff) > l.fieldsStart {
+		l.appendArrayDelim()
+	}
+	return l
+}
+
+func (a *Arr) Str(val string) *Arr {
+	a.elem().appendStr(val)
+	return a
+}
+
+func (a *Arr) Bytes(val []byte) *Arr {
+	a.elem().appendStr(string(val))
+	return a
+}
+
+func (a *Arr) Int(val int) *Arr {
+	a.elem().appendInt(int64(val))
+	return a
+}
+
+func (a *Arr) Int64(val int64) *Arr {
+	a.elem().appendInt(val)
+	return a
+}
+
+func (a *Arr) Uint(val uint) *Arr {
+	a.elem().appendUInt(uint64(val))
+	return a
+}
+
+func (a *Arr) Uint64(val uint64) *Arr {
+	a.elem().appendUInt(val)
+	return a
+}
+
+func (a *Arr) Float32(val float32) *Arr {
+	a.elem().appendFloat(float64(val), 32)
+	return a
+}
+
+func (a *Arr) Float64(val float64) *Arr {
+	a.elem().appendFloat(val, 64)
+	return a
+}
+
+func (a *Arr) Bool(val bool) *Arr {
+	a.elem().appendBool(val)
+	return a
+}
+
+func (a *Arr) Time(val time.Time) *Arr {
+	a.elem().appendTime(val)
+	return a
+}
+
+func (a *Arr) Dur(val time.Duration) *Arr {
+	a.elem().appendDur(val)
+	return a
+}
+
+// Err writes the message of the error.
+func (a *Arr) Err(err error) *Arr {
+	if err == nil {
+		return a
+	}
+
+	a.elem().appendStr(err.Error())
+	return a
+}
+
+// Dict writes an object with the fields written by fn.
+func (a *Arr) Dict(fn func(d *Line)) *Arr {
+	return a.Object(ObjectMarshalerFunc(fn))
+}
+
+func (a *Arr) Object(val ObjectMarshaler) *Arr {
+	a.elem().appendObject(val)
+	return a
+}
+
+// Array writes an array in the array.
+func (a *Arr) Array(fn func(a *Arr)) *Arr {
+	return a.ArrayOf(ArrayMarshalerFunc(fn))
+}
+
+func (a *Arr) ArrayOf(val ArrayMarshaler) *Arr {
+	a.elem().appendArray(val)
+	return a
+}
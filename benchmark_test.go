@@ -69,3 +69,105 @@ func BenchmarkStructuredLogging(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkDisabledLevel(b *testing.B) {
+	log := New(io.Discard, true)
+	log.SetLevel(InfoLevel)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			log.Debug().
+				Str("name", "user").
+				Int("age", 30).
+				Ints("age", ints).
+				Bool("married", true).
+				Err(err).
+				Msgf("%s", "a")
+		}
+	})
+}
+
+func BenchmarkSampledLogging(b *testing.B) {
+	log := New(io.Discard, true).Sample(NewSampler(10, 100, time.Second))
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			cl := log.With().Str("name", "User").Logger()
+			cl.Info().
+				Str("name", "user").
+				Int("age", 30).
+				Msg("sampled")
+			cl.EndWith()
+		}
+	})
+}
+
+func BenchmarkCaller(b *testing.B) {
+	log := New(io.Discard, true).SetCaller(&CallerConfig{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			log.Info().Str("name", "user").Msg("caller")
+		}
+	})
+}
+
+func BenchmarkDict(b *testing.B) {
+	log := New(io.Discard, true)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			log.Info().Dict("req", func(d *Line) {
+				d.Str("method", "GET").Str("path", "/users").Int("status", 200)
+			}).Msg("request")
+		}
+	})
+}
+
+func BenchmarkArray(b *testing.B) {
+	log := New(io.Discard, true)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			log.Info().Array("items", func(a *Arr) {
+				a.Dict(func(d *Line) { d.Str("id", "a1").Int("qty", 2).Float64("price", 9.5) })
+				a.Dict(func(d *Line) { d.Str("id", "b2").Int("qty", 1).Float64("price", 20) })
+			}).Msg("order")
+		}
+	})
+}
+
+type benchItem struct {
+	ID    string
+	Qty   int
+	Price float64
+}
+
+func (it *benchItem) MarshalLogObject(l *Line) {
+	l.Str("id", it.ID).Int("qty", it.Qty).Float64("price", it.Price)
+}
+
+type benchItems []benchItem
+
+func (items *benchItems) MarshalLogArray(a *Arr) {
+	for i := range *items {
+		a.Object(&(*items)[i])
+	}
+}
+
+func BenchmarkObjectMarshaler(b *testing.B) {
+	log := New(io.Discard, true)
+	items := benchItems{{ID: "a1", Qty: 2, Price: 9.5}, {ID: "b2", Qty: 1, Price: 20}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			log.Info().Object("first", &items[0]).ArrayOf("items", &items).Msg("order")
+		}
+	})
+}
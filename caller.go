@@ -0,0 +1,137 @@
+package gclog
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const callerKey = "caller"
+const callerFuncKey = "function"
+
+// CallerConfig adds the caller (the code which finished the line) to every line. Eg: caller="gclog/logger.go:42".
+type CallerConfig struct {
+	// Skip is the number of frames to skip. Eg: 1 for a helper function which wraps the logger.
+	Skip int
+	// FullPath writes the full path of the file. Otherwise, only the last dir and the file name.
+	FullPath bool
+	// Function adds a "function" field with the name of the function. Eg: "main.handleLogin".
+	Function bool
+}
+
+// SetCaller sets the caller config of the logger. Children created after this call inherit it.
+// Pass nil to stop adding the caller.
+func (l *Logger) SetCaller(c *CallerConfig) *Logger {
+	l.caller = c
+	return l
+}
+
+type callerFrame struct {
+	internal bool // Frame of gclog, log or log/slog. It is never the caller.
+	full     string
+	short    string
+	function string
+	frame    StackFrame
+}
+
+// callerCache maps a PC to its frames. A PC may have more than one frame because of inlining.
+var callerCache sync.Map // map[uintptr][]callerFrame
+
+// Frames of the functions with these prefixes are skipped to find the caller.
+var callerInternalPrefixes = []string{
+	gclogPkg + ".(*Line).",
+	gclogPkg + ".(*Logger).",
+	gclogPkg + ".(*SlogHandler).",
+	gclogPkg + ".(*stdLogWriter).",
+	"log.",
+	"log/slog.",
+}
+
+var gclogPkg = func() string {
+	name := runtime.FuncForPC(reflect.ValueOf(newLine).Pointer()).Name()
+	return name[:strings.LastIndexByte(name, '.')]
+}()
+
+func (l *Line) appendCaller(c *CallerConfig) {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:]) // 2 -> Skip runtime.Callers and appendCaller.
+
+	skip := c.Skip
+	for _, pc := range pcs[:n] {
+		for _, f := range callerFrames(pc) {
+			if f.internal {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+
+			l.appendKey(callerKey)
+			if c.FullPath {
+				l.appendStr(f.full)
+			} else {
+				l.appendStr(f.short)
+			}
+			if c.Function {
+				l.appendKey(callerFuncKey)
+				l.appendStr(f.function)
+			}
+			return
+		}
+	}
+}
+
+func callerFrames(pc uintptr) []callerFrame {
+	if cached, ok := callerCache.Load(pc); ok {
+		return cached.([]callerFrame)
+	}
+
+	var frames []callerFrame
+	rf := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := rf.Next()
+		if frame.Function != "" || frame.File != "" {
+			frames = append(frames, newCallerFrame(frame))
+		}
+		if !more {
+			break
+		}
+	}
+	callerCache.Store(pc, frames)
+	return frames
+}
+
+func newCallerFrame(frame runtime.Frame) callerFrame {
+	line := strconv.Itoa(frame.Line)
+	f := callerFrame{
+		full:     frame.File + ":" + line,
+		short:    shortPath(frame.File) + ":" + line,
+		function: frame.Function,
+		frame:    StackFrame{Function: frame.Function, File: frame.File, Line: frame.Line},
+	}
+	if i := strings.LastIndexByte(f.function, '/'); i >= 0 {
+		f.function = f.function[i+1:]
+	}
+	for _, prefix := range callerInternalPrefixes {
+		if strings.HasPrefix(frame.Function, prefix) {
+			f.internal = true
+			break
+		}
+	}
+	return f
+}
+
+// shortPath returns the last dir and the file name. Eg: "gclog/logger.go".
+func shortPath(file string) string {
+	i := strings.LastIndexByte(file, '/')
+	if i < 0 {
+		return file
+	}
+	if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+		return file[j+1:]
+	}
+	return file
+}
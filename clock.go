@@ -0,0 +1,22 @@
+package gclog
+
+import "time"
+
+// Clock returns the time of the lines. Eg: a fake clock for tests. See gclogtest.
+type Clock interface {
+	Now() time.Time
+}
+
+// SetClock sets the clock of the logger. Children created after this call inherit the clock.
+// Pass nil to use time.Now.
+func (l *Logger) SetClock(c Clock) *Logger {
+	l.clock = c
+	return l
+}
+
+func (l *Logger) now() time.Time {
+	if l.clock == nil {
+		return time.Now()
+	}
+	return l.clock.Now()
+}
@@ -0,0 +1,117 @@
+package gclog
+
+import (
+	"context"
+	"os"
+)
+
+// DefaultLogger is returned by FromContext if the context has no logger.
+var DefaultLogger = New(os.Stderr, false)
+
+type ctxKey struct{}
+type ctxFieldsKey struct{}
+
+// NewContext returns a copy of ctx which carries the logger. Request scoped
+// fields are added by storing a child. Eg: in a middleware,
+//
+//	ctx = gclog.NewContext(ctx, gclog.FromContext(ctx).With().Str("request_id", id).Logger())
+//
+// Then, gclog.FromContext(ctx).Info().Msg("hi") writes the request_id without passing it around.
+// See ContextWithFields to add fields without storing a logger.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// WithContext is same as NewContext(ctx, l).
+func (l *Logger) WithContext(ctx context.Context) context.Context {
+	return NewContext(ctx, l)
+}
+
+// FromContext returns the logger carried by ctx. It returns DefaultLogger if ctx has no logger.
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+	return DefaultLogger
+}
+
+// ContextWithFields returns a copy of ctx which carries the fields written by
+// fn, in addition to the fields already carried by ctx. They are written by the
+// lines started with Ctx, whichever logger writes them. Eg: in a middleware,
+//
+//	ctx = gclog.ContextWithFields(ctx, func(l *gclog.Line) { l.Str("request_id", id) })
+//
+// Then, gclog.Ctx(ctx).Info().Msg("hi") writes the request_id without passing it around.
+// fn is called for every line. So, it should be cheap. Eg: it should not format the values.
+func ContextWithFields(ctx context.Context, fn func(l *Line)) context.Context {
+	fields, _ := ctx.Value(ctxFieldsKey{}).([]func(l *Line))
+	return context.WithValue(ctx, ctxFieldsKey{}, append(fields[:len(fields):len(fields)], fn))
+}
+
+// CtxLogger starts lines which have the fields carried by a context. See ContextWithFields.
+type CtxLogger struct {
+	log *Logger
+	ctx context.Context
+}
+
+// Ctx returns the level entry points of the logger carried by ctx (See
+// FromContext). The lines have the fields carried by ctx. Eg:
+// gclog.Ctx(ctx).Info().Msg("hi").
+func Ctx(ctx context.Context) CtxLogger {
+	return CtxLogger{log: FromContext(ctx), ctx: ctx}
+}
+
+// Ctx is same as gclog.Ctx. But, the lines are written by l.
+func (l *Logger) Ctx(ctx context.Context) CtxLogger {
+	return CtxLogger{log: l, ctx: ctx}
+}
+
+// Logger returns the logger which writes the lines.
+func (c CtxLogger) Logger() *Logger {
+	return c.log
+}
+
+// WithLevel returns a nil *Line if lvl is below the minimum level.
+func (c CtxLogger) WithLevel(lvl Level) *Line {
+	line := c.log.WithLevel(lvl)
+	if line == nil || c.ctx == nil {
+		return line
+	}
+
+	fields, _ := c.ctx.Value(ctxFieldsKey{}).([]func(l *Line))
+	for _, fn := range fields {
+		fn(line)
+	}
+	return line
+}
+
+func (c CtxLogger) Trace() *Line {
+	return c.WithLevel(TraceLevel)
+}
+
+func (c CtxLogger) Debug() *Line {
+	return c.WithLevel(DebugLevel)
+}
+
+func (c CtxLogger) Info() *Line {
+	return c.WithLevel(InfoLevel)
+}
+
+func (c CtxLogger) Warn() *Line {
+	return c.WithLevel(WarnLevel)
+}
+
+// Fatal returns a line with "fatal" level. The process exits with status 1 once the line is finished.
+func (c CtxLogger) Fatal() *Line {
+	return c.WithLevel(FatalLevel)
+}
+
+// ContextWith returns a context Line of a child of the logger carried by ctx.
+// Use Line.Logger and NewContext to store the child. Eg:
+//
+//	ctx = gclog.NewContext(ctx, gclog.ContextWith(ctx).Str("request_id", id).Logger())
+func ContextWith(ctx context.Context) *Line {
+	return FromContext(ctx).With()
+}
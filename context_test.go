@@ -0,0 +1,63 @@
+package gclog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != DefaultLogger {
+		t.Errorf("FromContext of an empty context is not DefaultLogger")
+	}
+	var nilCtx context.Context
+	if got := FromContext(nilCtx); got != DefaultLogger {
+		t.Errorf("FromContext(nil) is not DefaultLogger")
+	}
+
+	l := New(&bytes.Buffer{}, true)
+	if got := FromContext(NewContext(context.Background(), l)); got != l {
+		t.Errorf("FromContext does not return the logger stored by NewContext")
+	}
+	if got := FromContext(l.WithContext(context.Background())); got != l {
+		t.Errorf("FromContext does not return the logger stored by WithContext")
+	}
+}
+
+func TestContextWith(t *testing.T) {
+	var b bytes.Buffer
+	l := NewWithEncoder(&b, JsonEncoder{Time: TimeConfig{Disabled: true}})
+	ctx := NewContext(context.Background(), l)
+	ctx = NewContext(ctx, ContextWith(ctx).Str("request_id", "r1").Logger())
+	FromContext(ctx).Info().Msg("hi")
+
+	want := `{"level":"info", "request_id":"r1", "msg":"hi"}` + "\n"
+	if b.String() != want {
+		t.Errorf("got %s, want %s", b.String(), want)
+	}
+}
+
+func TestCtx(t *testing.T) {
+	var b bytes.Buffer
+	l := NewWithEncoder(&b, JsonEncoder{Time: TimeConfig{Disabled: true}})
+	l.SetLevel(InfoLevel)
+	ctx := ContextWithFields(context.Background(), func(l *Line) { l.Str("request_id", "r1") })
+	ctx1 := ContextWithFields(ctx, func(l *Line) { l.Int("attempt", 1) })
+	ctx2 := ContextWithFields(ctx, func(l *Line) { l.Int("attempt", 2) })
+
+	// The fields are written by any logger, without storing it in the context.
+	l.Ctx(ctx1).Info().Msg("one")
+	l.Ctx(ctx2).Warn().Msg("two")
+	Ctx(NewContext(ctx, l)).Info().Msg("three")
+	if line := l.Ctx(ctx).Debug(); line != nil {
+		t.Errorf("got a line below the minimum level")
+	}
+
+	want := `{"level":"info", "request_id":"r1", "attempt":1, "msg":"one"}
+{"level":"warn", "request_id":"r1", "attempt":2, "msg":"two"}
+{"level":"info", "request_id":"r1", "msg":"three"}
+`
+	if b.String() != want {
+		t.Errorf("got\n%swant\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,88 @@
+package gclog
+
+import "time"
+
+// Encoder writes the parts of a line in an output format. Eg: JsonEncoder, TextEncoder and LogfmtEncoder.
+// All methods append to dst and return the extended buffer, like strconv.AppendInt.
+// An Encoder is shared by all the lines of a logger. So, it should not have any per line state.
+//
+// Colors are applied by the Line around the values written by the Encoder.
+// So, an Encoder should not write any colors.
+type Encoder interface {
+	// BeginLine is written at the start of every line. Eg: "{".
+	BeginLine(dst []byte) []byte
+	// AppendTimestamp writes the time of the line. It is followed by the fields. So, it should end with a separator.
+	AppendTimestamp(dst []byte, t time.Time) []byte
+	// EndLine is written at the end of every line. Eg: "}\n".
+	EndLine(dst []byte) []byte
+	// AppendDelim is written between two fields. Eg: ", ".
+	AppendDelim(dst []byte) []byte
+	// AppendKey writes the key of a field. Eg: `"key":`.
+	AppendKey(dst []byte, key string) []byte
+	// AppendMessage writes the message of Logger.Print. Eg: `"msg":"hello"`.
+	AppendMessage(dst []byte, msg string) []byte
+
+	AppendString(dst []byte, val string) []byte
+	AppendInt(dst []byte, val int64) []byte
+	AppendUint(dst []byte, val uint64) []byte
+	AppendFloat(dst []byte, val float64, bitSize int) []byte
+	AppendBool(dst []byte, val bool) []byte
+	AppendTime(dst []byte, val time.Time) []byte
+	AppendDuration(dst []byte, val time.Duration) []byte
+	AppendLevel(dst []byte, lvl Level) []byte
+
+	AppendArrayStart(dst []byte) []byte
+	AppendArrayDelim(dst []byte) []byte
+	// AppendArrayString writes a string in an array. Formats without arrays (Eg: logfmt) may need to write it differently.
+	AppendArrayString(dst []byte, val string) []byte
+	AppendArrayEnd(dst []byte) []byte
+
+	// CanColorize returns false if the format must never be colorized. Eg: logfmt.
+	CanColorize() bool
+}
+
+// The interfaces below are optional capabilities of an Encoder. The Line
+// checks them with a type assertion, and falls back to the methods of Encoder
+// if the encoder does not implement them. So, an Encoder written against an
+// older version keeps working.
+
+// ObjectEncoder is implemented by the encoders which write nested objects. Eg:
+// JsonEncoder. Without it, the fields of a nested object are written as fields
+// of the line, with dotted keys. Eg: req.method="GET". In an array, they are
+// written between "{" and "}".
+type ObjectEncoder interface {
+	AppendObjectStart(dst []byte) []byte
+	AppendObjectEnd(dst []byte) []byte
+}
+
+// StackEncoder writes the frames of a stack trace. Eg: an array of objects in
+// JSON, an indented block in text. Without it, the stack is written as a string with a line per frame.
+type StackEncoder interface {
+	AppendStack(dst []byte, frames []StackFrame) []byte
+}
+
+// ErrorCausesEncoder writes the errors wrapped by an error. Eg: an array of objects in JSON.
+// Without it, the causes are written as an array of strings. Eg: ["*fs.PathError: open a.txt: no such file"].
+type ErrorCausesEncoder interface {
+	AppendErrorCauses(dst []byte, causes []ErrorCause) []byte
+}
+
+// RawJSONEncoder writes a JSON encoded value. Eg: JsonEncoder. It returns an
+// error, and dst as it is, if val is not valid JSON. Without it, Line.RawJSON
+// writes the value as a string, and Line.Interface writes it in %+v form.
+type RawJSONEncoder interface {
+	AppendRawJSON(dst []byte, val []byte) ([]byte, error)
+}
+
+// NilEncoder writes a nil value. Eg: null. Without it, a nil slice is written as an empty array.
+type NilEncoder interface {
+	AppendNil(dst []byte) []byte
+}
+
+// ArrayQuoter is implemented by the encoders which write an array, with all
+// the values in it, as one quoted value. Eg: LogfmtEncoder. The Line calls
+// QuoteArray once the outermost array is written at dst[start:]. So, every
+// value in the array is escaped, including the quotes written by the encoder.
+type ArrayQuoter interface {
+	QuoteArray(dst []byte, start int) []byte
+}
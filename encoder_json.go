@@ -0,0 +1,178 @@
+package gclog
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+)
+
+// JsonEncoder writes every line as a JSON object. Eg:
+// {"time":1136214245000000, "level":"info", "user":"John", "msg":"hello"}
+type JsonEncoder struct {
+	// Default format: TimeFormatUnixMicro.
+	Time TimeConfig
+}
+
+func (JsonEncoder) BeginLine(dst []byte) []byte {
+	return append(dst, '{')
+}
+
+func (e JsonEncoder) AppendTimestamp(dst []byte, t time.Time) []byte {
+	if e.Time.Disabled {
+		return dst
+	}
+	dst = e.AppendKey(dst, e.Time.key())
+	dst = e.AppendTime(dst, t)
+	return append(dst, ',', ' ')
+}
+
+func (JsonEncoder) EndLine(dst []byte) []byte {
+	return append(dst, '}', '\n')
+}
+
+func (JsonEncoder) AppendDelim(dst []byte) []byte {
+	return append(dst, ',', ' ')
+}
+
+func (JsonEncoder) AppendKey(dst []byte, key string) []byte {
+	dst = append(dst, '"')
+	dst = appendSafeString(dst, key)
+	return append(dst, '"', ':')
+}
+
+func (e JsonEncoder) AppendMessage(dst []byte, msg string) []byte {
+	dst = e.AppendKey(dst, msgKey)
+	return e.AppendString(dst, msg)
+}
+
+func (JsonEncoder) AppendString(dst []byte, val string) []byte {
+	dst = append(dst, '"')
+	dst = appendSafeString(dst, val)
+	return append(dst, '"')
+}
+
+func (JsonEncoder) AppendInt(dst []byte, val int64) []byte {
+	return strconv.AppendInt(dst, val, 10)
+}
+
+func (JsonEncoder) AppendUint(dst []byte, val uint64) []byte {
+	return strconv.AppendUint(dst, val, 10)
+}
+
+func (JsonEncoder) AppendFloat(dst []byte, val float64, bitSize int) []byte {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		// JSON has no NaN and ∞.
+		return append(dst, "null"...)
+	}
+	return strconv.AppendFloat(dst, val, 'f', -1, bitSize)
+}
+
+func (JsonEncoder) AppendBool(dst []byte, val bool) []byte {
+	return strconv.AppendBool(dst, val)
+}
+
+func (e JsonEncoder) AppendTime(dst []byte, val time.Time) []byte {
+	format := e.Time.format(TimeFormatUnixMicro)
+	if unix, ok := e.Time.appendUnix(dst, val, format); ok {
+		return unix
+	}
+	dst = append(dst, '"')
+	dst = e.Time.appendLayout(dst, val, format)
+	return append(dst, '"')
+}
+
+func (JsonEncoder) AppendDuration(dst []byte, val time.Duration) []byte {
+	dst = append(dst, '"')
+	dst = strconv.AppendInt(dst, int64(val), 10)
+	return append(dst, '"')
+}
+
+func (JsonEncoder) AppendNil(dst []byte) []byte {
+	return append(dst, "null"...)
+}
+
+func (JsonEncoder) AppendLevel(dst []byte, lvl Level) []byte {
+	dst = append(dst, '"')
+	dst = append(dst, lvl.String()...)
+	return append(dst, '"')
+}
+
+func (e JsonEncoder) AppendStack(dst []byte, frames []StackFrame) []byte {
+	dst = append(dst, '[')
+	for i, f := range frames {
+		if i > 0 {
+			dst = append(dst, ',', ' ')
+		}
+		dst = append(dst, `{"function":`...)
+		dst = e.AppendString(dst, f.Function)
+		dst = append(dst, `, "file":`...)
+		dst = e.AppendString(dst, f.File)
+		dst = append(dst, `, "line":`...)
+		dst = strconv.AppendInt(dst, int64(f.Line), 10)
+		if f.Repeat > 1 {
+			dst = append(dst, `, "repeat":`...)
+			dst = strconv.AppendInt(dst, int64(f.Repeat), 10)
+		}
+		dst = append(dst, '}')
+	}
+	return append(dst, ']')
+}
+
+// AppendRawJSON writes val compacted. Ie: without the spaces and new lines between the tokens.
+// If val is not valid JSON, dst is returned as it is, with the error.
+func (JsonEncoder) AppendRawJSON(dst []byte, val []byte) ([]byte, error) {
+	if len(val) == 0 {
+		return append(dst, "null"...), nil
+	}
+
+	buf := bytes.NewBuffer(dst)
+	if err := json.Compact(buf, val); err != nil {
+		return dst, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (e JsonEncoder) AppendErrorCauses(dst []byte, causes []ErrorCause) []byte {
+	dst = append(dst, '[')
+	for i, c := range causes {
+		if i > 0 {
+			dst = append(dst, ',', ' ')
+		}
+		dst = append(dst, `{"msg":`...)
+		dst = e.AppendString(dst, c.Message)
+		dst = append(dst, `, "type":`...)
+		dst = e.AppendString(dst, c.Type)
+		dst = append(dst, '}')
+	}
+	return append(dst, ']')
+}
+
+func (JsonEncoder) AppendArrayStart(dst []byte) []byte {
+	return append(dst, '[')
+}
+
+func (JsonEncoder) AppendArrayDelim(dst []byte) []byte {
+	return append(dst, ',', ' ')
+}
+
+func (e JsonEncoder) AppendArrayString(dst []byte, val string) []byte {
+	return e.AppendString(dst, val)
+}
+
+func (JsonEncoder) AppendArrayEnd(dst []byte) []byte {
+	return append(dst, ']')
+}
+
+func (JsonEncoder) AppendObjectStart(dst []byte) []byte {
+	return append(dst, '{')
+}
+
+func (JsonEncoder) AppendObjectEnd(dst []byte) []byte {
+	return append(dst, '}')
+}
+
+func (JsonEncoder) CanColorize() bool {
+	return true
+}
@@ -4,7 +4,8 @@ import "unicode/utf8"
 
 const hex = "0123456789abcdef"
 
-func (l *Line) appendSafeString(s string) {
+// appendSafeString writes s escaped to be placed inside a JSON string.
+func appendSafeString(dst []byte, s string) []byte {
 	escapeHTML := true
 	start := 0
 	for i := 0; i < len(s); {
@@ -14,26 +15,26 @@ func (l *Line) appendSafeString(s string) {
 				continue
 			}
 			if start < i {
-				l.buff = append(l.buff, s[start:i]...)
+				dst = append(dst, s[start:i]...)
 			}
 
-			l.buff = append(l.buff, '\\')
+			dst = append(dst, '\\')
 			switch b {
 			case '\\', '"':
-				l.buff = append(l.buff, b)
+				dst = append(dst, b)
 			case '\n':
-				l.buff = append(l.buff, '\\', 'n')
+				dst = append(dst, 'n')
 			case '\r':
-				l.buff = append(l.buff, '\\', 'r')
+				dst = append(dst, 'r')
 			case '\t':
-				l.buff = append(l.buff, '\\', 't')
+				dst = append(dst, 't')
 			default:
 				// This encodes bytes < 0x20 except for \t, \n and \r.
 				// If escapeHTML is set, it also escapes <, >, and &
 				// because they can lead to security holes when
 				// user-controlled strings are rendered into JSON
 				// and served to some browsers.
-				l.buff = append(l.buff, 'u', '0', '0', hex[b>>4], hex[b&0xF])
+				dst = append(dst, 'u', '0', '0', hex[b>>4], hex[b&0xF])
 			}
 			i++
 			start = i
@@ -42,9 +43,9 @@ func (l *Line) appendSafeString(s string) {
 		c, size := utf8.DecodeRuneInString(s[i:])
 		if c == utf8.RuneError && size == 1 {
 			if start < i {
-				l.buff = append(l.buff, s[start:i]...)
+				dst = append(dst, s[start:i]...)
 			}
-			l.buff = append(l.buff, `\ufffd`...)
+			dst = append(dst, `\ufffd`...)
 			i += size
 			start = i
 			continue
@@ -58,10 +59,10 @@ func (l *Line) appendSafeString(s string) {
 		// See http://timelessrepo.com/json-isnt-a-javascript-subset for discussion.
 		if c == '\u2028' || c == '\u2029' {
 			if start < i {
-				l.buff = append(l.buff, s[start:i]...)
+				dst = append(dst, s[start:i]...)
 			}
-			l.buff = append(l.buff, `\u202`...)
-			l.buff = append(l.buff, hex[c&0xF])
+			dst = append(dst, `\u202`...)
+			dst = append(dst, hex[c&0xF])
 			i += size
 			start = i
 			continue
@@ -69,6 +70,7 @@ func (l *Line) appendSafeString(s string) {
 		i += size
 	}
 	if start < len(s) {
-		l.buff = append(l.buff, s[start:]...)
+		dst = append(dst, s[start:]...)
 	}
+	return dst
 }
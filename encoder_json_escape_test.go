@@ -0,0 +1,34 @@
+package gclog
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAppendSafeString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", `plain`},
+		{"a\nb", `a\nb`},
+		{"a\rb", `a\rb`},
+		{"a\tb", `a\tb`},
+		{`q"b\s`, `q\"b\\s`},
+		{"\x01", `\u0001`},
+		{"<&>", `\u003c\u0026\u003e`},
+		{"bad\xff", `bad\ufffd`},
+		{"\u2028", `\u2028`},
+	}
+	for _, tt := range tests {
+		got := string(appendSafeString(nil, tt.in))
+		if got != tt.want {
+			t.Errorf("appendSafeString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+
+		var decoded string
+		if err := json.Unmarshal([]byte(`"`+got+`"`), &decoded); err != nil {
+			t.Errorf("appendSafeString(%q) is not a valid JSON string: %v", tt.in, err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package gclog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestRawJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"compact", `{"a":1}`, `"v":{"a":1}`},
+		{"pretty", "{\n  \"a\": [1, 2],\n  \"b\": \"x y\"\n}\n", `"v":{"a":[1,2],"b":"x y"}`},
+		{"empty", ``, `"v":null`},
+		{"invalid", `{"a":`, `"v":"{\"a\":", "v_err":"unexpected end of JSON input"`},
+		{"two values", `1 2`, `"v":"1 2", "v_err":"invalid character '2' after top-level value"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			l := NewWithEncoder(&b, JsonEncoder{Time: TimeConfig{Disabled: true}})
+			l.Info().RawJSON("v", []byte(tt.in)).Msg("hi")
+
+			if !bytes.Contains(b.Bytes(), []byte(tt.want)) {
+				t.Errorf("got %s, want it to contain %s", b.String(), tt.want)
+			}
+			if !json.Valid(b.Bytes()) {
+				t.Errorf("not a valid JSON line: %s", b.String())
+			}
+		})
+	}
+}
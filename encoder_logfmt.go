@@ -0,0 +1,140 @@
+package gclog
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// LogfmtEncoder writes lines in logfmt format. Eg:
+// time=2006-01-02T15:04:05.000000Z07:00 level=info user="John Doe" msg=hello
+// logfmt has no arrays. So, arrays are written as quoted values. Eg: ids="[1, 2]".
+type LogfmtEncoder struct {
+	// Default format: time.RFC3339Nano.
+	Time TimeConfig
+}
+
+func (LogfmtEncoder) BeginLine(dst []byte) []byte {
+	return dst
+}
+
+func (e LogfmtEncoder) AppendTimestamp(dst []byte, t time.Time) []byte {
+	if e.Time.Disabled {
+		return dst
+	}
+	dst = e.AppendKey(dst, e.Time.key())
+	dst = e.AppendTime(dst, t)
+	return append(dst, ' ')
+}
+
+func (LogfmtEncoder) EndLine(dst []byte) []byte {
+	return append(dst, '\n')
+}
+
+func (LogfmtEncoder) AppendDelim(dst []byte) []byte {
+	return append(dst, ' ')
+}
+
+func (LogfmtEncoder) AppendKey(dst []byte, key string) []byte {
+	dst = appendLogfmtKey(dst, key)
+	return append(dst, '=')
+}
+
+func (e LogfmtEncoder) AppendMessage(dst []byte, msg string) []byte {
+	dst = e.AppendKey(dst, msgKey)
+	return e.AppendString(dst, msg)
+}
+
+func (LogfmtEncoder) AppendString(dst []byte, val string) []byte {
+	return appendLogfmtStr(dst, val)
+}
+
+func (LogfmtEncoder) AppendInt(dst []byte, val int64) []byte {
+	return strconv.AppendInt(dst, val, 10)
+}
+
+func (LogfmtEncoder) AppendUint(dst []byte, val uint64) []byte {
+	return strconv.AppendUint(dst, val, 10)
+}
+
+func (LogfmtEncoder) AppendFloat(dst []byte, val float64, bitSize int) []byte {
+	switch {
+	case math.IsNaN(val):
+		return append(dst, "NaN"...)
+	case math.IsInf(val, -1):
+		return append(dst, "-Inf"...)
+	case math.IsInf(val, 1):
+		return append(dst, "+Inf"...)
+	}
+	return strconv.AppendFloat(dst, val, 'f', -1, bitSize)
+}
+
+func (LogfmtEncoder) AppendBool(dst []byte, val bool) []byte {
+	return strconv.AppendBool(dst, val)
+}
+
+func (e LogfmtEncoder) AppendTime(dst []byte, val time.Time) []byte {
+	format := e.Time.format(time.RFC3339Nano)
+	if unix, ok := e.Time.appendUnix(dst, val, format); ok {
+		return unix
+	}
+	start := len(dst)
+	dst = e.Time.appendLayout(dst, val, format)
+	if logfmtNeedsQuote(string(dst[start:])) {
+		// Custom layouts may have spaces.
+		formatted := string(dst[start:])
+		dst = appendLogfmtStr(dst[:start], formatted)
+	}
+	return dst
+}
+
+func (LogfmtEncoder) AppendDuration(dst []byte, val time.Duration) []byte {
+	return append(dst, val.String()...)
+}
+
+func (LogfmtEncoder) AppendNil(dst []byte) []byte {
+	return append(dst, "nil"...)
+}
+
+func (LogfmtEncoder) AppendLevel(dst []byte, lvl Level) []byte {
+	return append(dst, lvl.String()...)
+}
+
+func (LogfmtEncoder) AppendArrayStart(dst []byte) []byte {
+	// The whole array is quoted by QuoteArray.
+	return append(dst, '[')
+}
+
+func (LogfmtEncoder) AppendArrayDelim(dst []byte) []byte {
+	return append(dst, ',', ' ')
+}
+
+func (LogfmtEncoder) AppendArrayString(dst []byte, val string) []byte {
+	// The whole array is escaped by QuoteArray.
+	return append(dst, val...)
+}
+
+func (LogfmtEncoder) AppendArrayEnd(dst []byte) []byte {
+	return append(dst, ']')
+}
+
+// QuoteArray writes the array at dst[start:], with everything in it, as one
+// quoted value. Eg: "[a b, {id=1 err=\"x y\"}]".
+func (LogfmtEncoder) QuoteArray(dst []byte, start int) []byte {
+	if !logfmtNeedsEscape(dst[start:]) {
+		// Insert the opening quote without copying the array.
+		dst = append(dst, 0)
+		copy(dst[start+1:], dst[start:len(dst)-1])
+		dst[start] = '"'
+		return append(dst, '"')
+	}
+
+	arr := string(dst[start:])
+	dst = append(dst[:start], '"')
+	dst = appendLogfmtEscaped(dst, arr)
+	return append(dst, '"')
+}
+
+func (LogfmtEncoder) CanColorize() bool {
+	return false
+}
@@ -0,0 +1,100 @@
+package gclog
+
+import "unicode/utf8"
+
+// appendLogfmtKey writes the key. Chars which are not allowed in a logfmt key
+// (space, control chars, '=' and '"') are replaced with '_'.
+func appendLogfmtKey(dst []byte, key string) []byte {
+	if key == "" {
+		return append(dst, '_')
+	}
+
+	for i := 0; i < len(key); {
+		c, size := utf8.DecodeRuneInString(key[i:])
+		if c <= ' ' || c == '=' || c == '"' || c == utf8.RuneError {
+			dst = append(dst, '_')
+		} else {
+			dst = append(dst, key[i:i+size]...)
+		}
+		i += size
+	}
+	return dst
+}
+
+// appendLogfmtStr writes the value. It is quoted only if needed. Ie: if it
+// is empty, or has space, control chars, '=' or '"'.
+func appendLogfmtStr(dst []byte, val string) []byte {
+	if !logfmtNeedsQuote(val) {
+		return append(dst, val...)
+	}
+
+	dst = append(dst, '"')
+	dst = appendLogfmtEscaped(dst, val)
+	return append(dst, '"')
+}
+
+func logfmtNeedsQuote(val string) bool {
+	if val == "" {
+		return true
+	}
+	for i := 0; i < len(val); {
+		c, size := utf8.DecodeRuneInString(val[i:])
+		if c <= ' ' || c == '=' || c == '"' || c == utf8.RuneError {
+			return true
+		}
+		i += size
+	}
+	return false
+}
+
+// logfmtNeedsEscape returns false if appendLogfmtEscaped writes val as it is.
+func logfmtNeedsEscape(val []byte) bool {
+	for _, b := range val {
+		if b < ' ' || b == '"' || b == '\\' || b >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
+
+// appendLogfmtEscaped writes the value to be placed inside quotes.
+func appendLogfmtEscaped(dst []byte, val string) []byte {
+	start := 0
+	for i := 0; i < len(val); {
+		b := val[i]
+		if b >= utf8.RuneSelf {
+			c, size := utf8.DecodeRuneInString(val[i:])
+			if c == utf8.RuneError && size == 1 {
+				dst = append(dst, val[start:i]...)
+				dst = append(dst, `�`...)
+				i += size
+				start = i
+				continue
+			}
+			i += size
+			continue
+		}
+		if b >= ' ' && b != '"' && b != '\\' {
+			i++
+			continue
+		}
+
+		dst = append(dst, val[start:i]...)
+		dst = append(dst, '\\')
+		switch b {
+		case '\\', '"':
+			dst = append(dst, b)
+		case '\n':
+			dst = append(dst, 'n')
+		case '\r':
+			dst = append(dst, 'r')
+		case '\t':
+			dst = append(dst, 't')
+		default:
+			dst = append(dst, 'u', '0', '0', hex[b>>4], hex[b&0xF])
+		}
+		i++
+		start = i
+	}
+	return append(dst, val[start:]...)
+}
@@ -0,0 +1,65 @@
+package gclog
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAppendLogfmtStr(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", `plain`},
+		{"", `""`},
+		{"a b", `"a b"`},
+		{"a=b", `"a=b"`},
+		{`a"b`, `"a\"b"`},
+		{`a\b`, `a\b`},
+		{`a\ b`, `"a\\ b"`},
+		{"a\nb", `"a\nb"`},
+		{"a\rb", `"a\rb"`},
+		{"a\tb", `"a\tb"`},
+		{"\x01", `"\u0001"`},
+		{"héllo", `héllo`},
+		{"bad\xff", `"bad�"`},
+	}
+	for _, tt := range tests {
+		if got := string(appendLogfmtStr(nil, tt.in)); got != tt.want {
+			t.Errorf("appendLogfmtStr(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAppendLogfmtKey(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"key", `key`},
+		{"req.method", `req.method`},
+		{"", `_`},
+		{"a b", `a_b`},
+		{"a=b", `a_b`},
+		{`a"b`, `a_b`},
+		{"a\nb", `a_b`},
+		{"héllo", `héllo`},
+		{"bad\xff", `bad_`},
+	}
+	for _, tt := range tests {
+		if got := string(appendLogfmtKey(nil, tt.in)); got != tt.want {
+			t.Errorf("appendLogfmtKey(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLogfmtLine(t *testing.T) {
+	var b bytes.Buffer
+	l := NewWithEncoder(&b, LogfmtEncoder{Time: TimeConfig{Disabled: true}})
+	l.Info().Str("a b", "x y").Strs("s", []string{`q"`, "n\n"}).Int("n", 1).Msg("hi there")
+
+	want := `level=info a_b="x y" s="[q\", n\n]" n=1 msg="hi there"` + "\n"
+	if b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+}
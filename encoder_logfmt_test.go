@@ -0,0 +1,108 @@
+package gclog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseLogfmt parses a logfmt line. Quoted values are unquoted like Go strings.
+func parseLogfmt(t *testing.T, line string) map[string]string {
+	t.Helper()
+	fields := map[string]string{}
+	line = strings.TrimSuffix(line, "\n")
+	for len(line) > 0 {
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 || strings.ContainsAny(line[:eq], " \"") {
+			t.Fatalf("invalid key at %q", line)
+		}
+		key := line[:eq]
+		line = line[eq+1:]
+
+		var val string
+		if strings.HasPrefix(line, `"`) {
+			end := 1
+			for ; end < len(line) && line[end] != '"'; end++ {
+				if line[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(line) {
+				t.Fatalf("unterminated value of %s: %q", key, line)
+			}
+			var err error
+			if val, err = strconv.Unquote(line[:end+1]); err != nil {
+				t.Fatalf("invalid value of %s %s: %v", key, line[:end+1], err)
+			}
+			line = line[end+1:]
+		} else {
+			end := strings.IndexByte(line, ' ')
+			if end < 0 {
+				end = len(line)
+			}
+			val, line = line[:end], line[end:]
+			if strings.ContainsRune(val, '"') {
+				t.Fatalf("unquoted value of %s has a quote: %s", key, val)
+			}
+		}
+		if len(line) > 0 {
+			if line[0] != ' ' {
+				t.Fatalf("no space after the value of %s: %q", key, line)
+			}
+			line = line[1:]
+		}
+		fields[key] = val
+	}
+	return fields
+}
+
+func TestLogfmtArraysAreEscaped(t *testing.T) {
+	var b bytes.Buffer
+	enc := LogfmtEncoder{Time: TimeConfig{Disabled: true}}
+	l := NewWithEncoder(&b, enc)
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	l.Info().Array("items", func(a *Arr) {
+		a.Dict(func(d *Line) {
+			d.Str("id", "a b").
+				Err(fmt.Errorf("outer: %w", errors.New("in ner"))).
+				Interface("v", struct{ A string }{"x y"}).
+				Strs("tags", []string{`q"`, "n\n"})
+		})
+		a.Array(func(a *Arr) { a.Str("c d").Time(at) })
+	}).Strs("plain", []string{"a", "b"}).Msg("hi")
+
+	fields := parseLogfmt(t, b.String())
+	want := `[{id="a b" err="outer: in ner" causes=[*errors.errorString: in ner] v="{A:x y}" tags=[q", n` + "\n" + `]}, [c d, 2020-01-02T03:04:05Z]]`
+	if fields["items"] != want {
+		t.Errorf("items = %s\nwant    %s\nline: %s", fields["items"], want, b.String())
+	}
+	if fields["plain"] != "[a, b]" || fields["msg"] != "hi" {
+		t.Errorf("got %v", fields)
+	}
+}
+
+func TestLogfmtArrayWithoutEscapes(t *testing.T) {
+	var b bytes.Buffer
+	l := NewWithEncoder(&b, LogfmtEncoder{Time: TimeConfig{Disabled: true}})
+	l.Info().Ints("n", []int{1, 2}).Ints("e", []int{}).Msg("hi")
+
+	want := `level=info n="[1, 2]" e="[]" msg=hi` + "\n"
+	if b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+}
+
+func TestLogfmtErrorCauses(t *testing.T) {
+	var b bytes.Buffer
+	l := NewWithEncoder(&b, LogfmtEncoder{Time: TimeConfig{Disabled: true}})
+	l.Info().Err(fmt.Errorf("outer: %w", errors.New(`a "b"`))).Msg("hi")
+
+	fields := parseLogfmt(t, b.String())
+	if want := `[*errors.errorString: a "b"]`; fields["causes"] != want {
+		t.Errorf("causes = %s, want %s\nline: %s", fields["causes"], want, b.String())
+	}
+}
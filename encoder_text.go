@@ -0,0 +1,144 @@
+package gclog
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+const textTimeFormat = "2006/01/02 15:04:05"
+
+// TextEncoder writes human readable lines. Eg:
+// 2006/01/02 15:04:05 level=info, user="John", msg="hello"
+type TextEncoder struct {
+	// Default format: "2006/01/02 15:04:05" in local time.
+	Time TimeConfig
+}
+
+func (TextEncoder) BeginLine(dst []byte) []byte {
+	return dst
+}
+
+func (e TextEncoder) AppendTimestamp(dst []byte, t time.Time) []byte {
+	if e.Time.Disabled {
+		return dst
+	}
+	if e.Time.Key != "" {
+		dst = e.AppendKey(dst, e.Time.Key)
+	}
+	dst = e.AppendTime(dst, t)
+	return append(dst, ' ')
+}
+
+func (TextEncoder) EndLine(dst []byte) []byte {
+	return append(dst, '\n')
+}
+
+func (TextEncoder) AppendDelim(dst []byte) []byte {
+	return append(dst, ',', ' ')
+}
+
+func (TextEncoder) AppendKey(dst []byte, key string) []byte {
+	dst = append(dst, key...)
+	return append(dst, '=')
+}
+
+func (TextEncoder) AppendMessage(dst []byte, msg string) []byte {
+	return append(dst, msg...)
+}
+
+// AppendString writes the quoted string. '"', '\\', new lines and carriage returns are escaped. So, a line is never split.
+func (TextEncoder) AppendString(dst []byte, val string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(val); i++ {
+		var esc byte
+		switch val[i] {
+		case '"', '\\':
+			esc = val[i]
+		case '\n':
+			esc = 'n'
+		case '\r':
+			esc = 'r'
+		default:
+			continue
+		}
+		dst = append(dst, val[start:i]...)
+		dst = append(dst, '\\', esc)
+		start = i + 1
+	}
+	dst = append(dst, val[start:]...)
+	return append(dst, '"')
+}
+
+func (TextEncoder) AppendInt(dst []byte, val int64) []byte {
+	return strconv.AppendInt(dst, val, 10)
+}
+
+func (TextEncoder) AppendUint(dst []byte, val uint64) []byte {
+	return strconv.AppendUint(dst, val, 10)
+}
+
+func (TextEncoder) AppendFloat(dst []byte, val float64, bitSize int) []byte {
+	switch {
+	case math.IsNaN(val):
+		return append(dst, "'NaN'"...)
+	case math.IsInf(val, -1):
+		return append(dst, "'-∞'"...)
+	case math.IsInf(val, 1):
+		return append(dst, "'∞'"...)
+	}
+	return strconv.AppendFloat(dst, val, 'f', -1, bitSize)
+}
+
+func (TextEncoder) AppendBool(dst []byte, val bool) []byte {
+	return strconv.AppendBool(dst, val)
+}
+
+func (e TextEncoder) AppendTime(dst []byte, val time.Time) []byte {
+	format := e.Time.format(textTimeFormat)
+	if unix, ok := e.Time.appendUnix(dst, val, format); ok {
+		return unix
+	}
+	return e.Time.appendLayout(dst, val, format)
+}
+
+func (e TextEncoder) AppendDuration(dst []byte, val time.Duration) []byte {
+	return e.AppendString(dst, val.String())
+}
+
+func (TextEncoder) AppendNil(dst []byte) []byte {
+	return append(dst, "nil"...)
+}
+
+func (TextEncoder) AppendLevel(dst []byte, lvl Level) []byte {
+	return append(dst, lvl.String()...)
+}
+
+func (TextEncoder) AppendStack(dst []byte, frames []StackFrame) []byte {
+	return appendTextStack(dst, frames)
+}
+
+func (e TextEncoder) AppendErrorCauses(dst []byte, causes []ErrorCause) []byte {
+	return appendTextErrCauses(dst, causes, e.AppendString)
+}
+
+func (TextEncoder) AppendArrayStart(dst []byte) []byte {
+	return append(dst, '[')
+}
+
+func (TextEncoder) AppendArrayDelim(dst []byte) []byte {
+	return append(dst, ',', ' ')
+}
+
+func (e TextEncoder) AppendArrayString(dst []byte, val string) []byte {
+	return e.AppendString(dst, val)
+}
+
+func (TextEncoder) AppendArrayEnd(dst []byte) []byte {
+	return append(dst, ']')
+}
+
+func (TextEncoder) CanColorize() bool {
+	return true
+}
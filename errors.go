@@ -0,0 +1,107 @@
+package gclog
+
+import "reflect"
+
+const errKey = "err"
+const errCausesKey = "causes"
+
+// Maximum number of causes written for an error. It also stops a cyclic chain.
+const maxErrCauses = 32
+
+// ErrorCause is an error wrapped by the error passed to Line.Err.
+type ErrorCause struct {
+	Message string
+	Type    string // Go type of the error. Eg: "*fs.PathError".
+}
+
+// ErrorMarshaler is implemented by errors which add their own fields to the
+// line. Eg: an HTTP status code or a retryable flag. Line.Err calls it for the
+// error and for every error wrapped by it.
+type ErrorMarshaler interface {
+	MarshalLogError(l *Line)
+}
+
+// appendErrChain writes the causes of err, and the fields of the errors which implement ErrorMarshaler.
+func (l *Line) appendErrChain(err error) {
+	var causes []ErrorCause
+	var marshalers []ErrorMarshaler
+	if m, ok := err.(ErrorMarshaler); ok {
+		marshalers = append(marshalers, m)
+	}
+	walkErrChain(err, func(e error) bool {
+		causes = append(causes, ErrorCause{Message: e.Error(), Type: reflect.TypeOf(e).String()})
+		if m, ok := e.(ErrorMarshaler); ok {
+			marshalers = append(marshalers, m)
+		}
+		return len(causes) < maxErrCauses
+	})
+
+	if len(causes) > 0 {
+		l.appendKey(errCausesKey)
+		l.appendErrCauses(causes)
+	}
+	for _, m := range marshalers {
+		m.MarshalLogError(l)
+	}
+}
+
+func (l *Line) appendErrCauses(causes []ErrorCause) {
+	ce, ok := l.enc.(ErrorCausesEncoder)
+	if !ok {
+		inArray := l.appendArrayStart()
+		for i, c := range causes {
+			if i > 0 {
+				l.appendArrayDelim()
+			}
+			l.appendErrStr(c.Type + ": " + c.Message)
+		}
+		l.appendArrayEnd(inArray)
+		return
+	}
+
+	if l.canColorize {
+		l.buff = append(l.buff, styleValErrStart...)
+	}
+	l.buff = ce.AppendErrorCauses(l.buff, causes)
+	if l.canColorize {
+		l.buff = append(l.buff, styleValErrEnd...)
+	}
+}
+
+// walkErrChain calls fn for every error wrapped by err, depth first. Both
+// Unwrap() error and Unwrap() []error (Eg: errors.Join) are followed. It stops if fn returns false.
+func walkErrChain(err error, fn func(e error) bool) bool {
+	var wrapped []error
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if inner := e.Unwrap(); inner != nil {
+			wrapped = []error{inner}
+		}
+	case interface{ Unwrap() []error }:
+		wrapped = e.Unwrap()
+	}
+
+	for _, inner := range wrapped {
+		if inner == nil {
+			continue
+		}
+		if !fn(inner) || !walkErrChain(inner, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// appendTextErrCauses writes the causes as a list of type and message. Eg: [*fs.PathError: "open a.txt: no such file"].
+func appendTextErrCauses(dst []byte, causes []ErrorCause, appendStr func(dst []byte, val string) []byte) []byte {
+	dst = append(dst, '[')
+	for i, c := range causes {
+		if i > 0 {
+			dst = append(dst, ',', ' ')
+		}
+		dst = append(dst, c.Type...)
+		dst = append(dst, ':', ' ')
+		dst = appendStr(dst, c.Message)
+	}
+	return append(dst, ']')
+}
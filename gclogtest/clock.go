@@ -0,0 +1,36 @@
+package gclogtest
+
+import (
+	"sync"
+	"time"
+)
+
+// FixedClock always returns the same time.
+type FixedClock struct {
+	T time.Time
+}
+
+func (c FixedClock) Now() time.Time {
+	return c.T
+}
+
+// StepClock returns start on the first call. Every call after that returns step later than the previous one.
+// It is safe to use from multiple goroutines.
+type StepClock struct {
+	mut  sync.Mutex
+	next time.Time
+	step time.Duration
+}
+
+func NewStepClock(start time.Time, step time.Duration) *StepClock {
+	return &StepClock{next: start, step: step}
+}
+
+func (c *StepClock) Now() time.Time {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	t := c.next
+	c.next = c.next.Add(c.step)
+	return t
+}
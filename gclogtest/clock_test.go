@@ -0,0 +1,28 @@
+package gclogtest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/arafath-mk/gclog"
+)
+
+func TestStepClockWithSampler(t *testing.T) {
+	var b bytes.Buffer
+	l := gclog.NewWithEncoder(&b, gclog.JsonEncoder{Time: gclog.TimeConfig{Format: gclog.TimeFormatUnixMs}}).
+		SetClock(NewStepClock(time.UnixMilli(100), 100*time.Millisecond)).
+		Sample(gclog.NewSampler(10, 0, time.Minute))
+	for i := 0; i < 3; i++ {
+		l.Info().Msg("hi")
+	}
+
+	// The clock moves a step per line, though it is read by both the sampler and the timestamp.
+	want := `{"time":100, "level":"info", "msg":"hi"}
+{"time":200, "level":"info", "msg":"hi"}
+{"time":300, "level":"info", "msg":"hi"}
+`
+	if b.String() != want {
+		t.Errorf("got\n%swant\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,165 @@
+package gclogtest
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/arafath-mk/gclog"
+)
+
+// Entry is a recorded line.
+type Entry struct {
+	Time    time.Time
+	Level   gclog.Level // NoLevel, if the line has no level.
+	Logger  string      // Name of the logger. See gclog.Logger.Named.
+	Message string
+	// Fields other than time, level, logger and msg. Numbers are int64, uint64
+	// or float64. Arrays are []any and objects are map[string]any.
+	Fields map[string]any
+}
+
+// Has returns whether the entry has the field with the value. Eg: Has("user_id", 42).
+// val is compared with the recorded value as it is written by the logger. So, it can be
+// of any number type, a time.Duration, a time.Time, or a slice of them (Eg: []string).
+func (e Entry) Has(key string, val any) bool {
+	v, ok := e.Fields[key]
+	if !ok {
+		return false
+	}
+	return equal(v, val)
+}
+
+type Entries []Entry
+
+// Level returns the entries with the level.
+func (es Entries) Level(lvl gclog.Level) Entries {
+	return es.filter(func(e Entry) bool { return e.Level == lvl })
+}
+
+// Message returns the entries with the message.
+func (es Entries) Message(msg string) Entries {
+	return es.filter(func(e Entry) bool { return e.Message == msg })
+}
+
+// Logger returns the entries of the named logger.
+func (es Entries) Logger(name string) Entries {
+	return es.filter(func(e Entry) bool { return e.Logger == name })
+}
+
+// Field returns the entries with the field and value. See Entry.Has.
+func (es Entries) Field(key string, val any) Entries {
+	return es.filter(func(e Entry) bool { return e.Has(key, val) })
+}
+
+func (es Entries) Len() int {
+	return len(es)
+}
+
+// AssertLen fails the test if there are not exactly n entries.
+// Eg: l.Entries().Level(gclog.WarnLevel).Field("user_id", 42).AssertLen(t, 1).
+func (es Entries) AssertLen(t testing.TB, n int) {
+	t.Helper()
+	if len(es) != n {
+		t.Errorf("gclogtest: want %d entries, got %d: %+v", n, len(es), es)
+	}
+}
+
+func (es Entries) filter(keep func(e Entry) bool) Entries {
+	var filtered Entries
+	for _, e := range es {
+		if keep(e) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// equal returns whether the recorded value is same as val.
+func equal(recorded any, val any) bool {
+	switch v := val.(type) {
+	case nil:
+		return recorded == nil
+	case time.Duration:
+		// Durations are written as the number of nanoseconds in a string.
+		return recorded == strconv.FormatInt(int64(v), 10)
+	case time.Time:
+		s, ok := recorded.(string)
+		if !ok {
+			return false
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil && t.Equal(v)
+	case []byte:
+		// Written as a string by Line.Bytes, or as an array of numbers by Line.Uints8.
+		if s, ok := recorded.(string); ok {
+			return s == string(v)
+		}
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return equalNumber(recorded, rv.Int(), 0, false, float64(rv.Int()), false)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return equalNumber(recorded, 0, rv.Uint(), true, float64(rv.Uint()), false)
+	case reflect.Float32, reflect.Float64:
+		return equalNumber(recorded, 0, 0, false, rv.Float(), true)
+	case reflect.Slice, reflect.Array:
+		recs, ok := recorded.([]any)
+		if !ok {
+			// A nil slice may be written as null.
+			return recorded == nil && rv.Kind() == reflect.Slice && rv.IsNil()
+		}
+		if len(recs) != rv.Len() {
+			return false
+		}
+		for i := range recs {
+			if !equal(recs[i], rv.Index(i).Interface()) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		recs, ok := recorded.(map[string]any)
+		if !ok || rv.Type().Key().Kind() != reflect.String || len(recs) != rv.Len() {
+			return false
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			r, ok := recs[iter.Key().String()]
+			if !ok || !equal(r, iter.Value().Interface()) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(recorded, val)
+}
+
+// equalNumber compares the recorded number with the int i, the uint u (if isUint) or the float f (if isFloat).
+// JSON has no number types. So, a float like 2.0 is recorded as int64(2), and it is same as 2.
+func equalNumber(recorded any, i int64, u uint64, isUint bool, f float64, isFloat bool) bool {
+	switch r := recorded.(type) {
+	case int64:
+		if isFloat {
+			return float64(r) == f
+		}
+		if isUint {
+			return r >= 0 && uint64(r) == u
+		}
+		return r == i
+	case uint64:
+		if isFloat {
+			return float64(r) == f
+		}
+		if isUint {
+			return r == u
+		}
+		return i >= 0 && uint64(i) == r
+	case float64:
+		return r == f
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package gclogtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arafath-mk/gclog"
+)
+
+// FixedTime is the time of all the lines of the Logger.
+var FixedTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const (
+	levelKey  = "level"
+	loggerKey = "logger"
+	msgKey    = "msg"
+)
+
+// Logger is a gclog.Logger which records every line as an Entry.
+// Its lines are not colorized and their time is always FixedTime.
+type Logger struct {
+	*gclog.Logger
+	rec *recorder
+}
+
+// New returns a Logger. If t is not nil, every line is also written to t.Log.
+func New(t testing.TB) *Logger {
+	rec := &recorder{t: t}
+	enc := gclog.JsonEncoder{Time: gclog.TimeConfig{Format: time.RFC3339Nano, UTC: true}}
+	l := gclog.NewWithEncoder(rec, enc).SetClock(FixedClock{T: FixedTime})
+	// The levels of GCLOG_LEVEL are not used. So, a test records all the lines, whatever the env of CI is.
+	l.SetLevel(gclog.TraceLevel)
+	resetEnvLevels(l)
+	return &Logger{Logger: l, rec: rec}
+}
+
+// resetEnvLevels resets the named levels set from GCLOG_LEVEL by gclog.NewWithEncoder.
+func resetEnvLevels(l *gclog.Logger) {
+	for _, entry := range strings.Split(os.Getenv(gclog.LevelEnv), ",") {
+		if name, _, named := strings.Cut(entry, "="); named {
+			l.ResetNamedLevel(strings.TrimSpace(name))
+		}
+	}
+}
+
+// Entries returns the lines recorded so far, including the lines of the children.
+func (l *Logger) Entries() Entries {
+	l.Flush()
+	return l.rec.entries()
+}
+
+// Reset removes the recorded lines.
+func (l *Logger) Reset() {
+	l.Flush()
+	l.rec.reset()
+}
+
+type recorder struct {
+	t    testing.TB
+	mut  sync.Mutex
+	recs Entries
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	e, err := parseEntry(p)
+
+	r.mut.Lock()
+	if err == nil {
+		r.recs = append(r.recs, e)
+	}
+	r.mut.Unlock()
+
+	if r.t != nil {
+		r.t.Helper()
+		if err != nil {
+			r.t.Errorf("gclogtest: can not parse line %q: %v", p, err)
+		} else {
+			r.t.Log(string(bytes.TrimSuffix(p, []byte{'\n'})))
+		}
+	}
+	return len(p), nil
+}
+
+func (r *recorder) entries() Entries {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	entries := make(Entries, len(r.recs))
+	copy(entries, r.recs)
+	return entries
+}
+
+func (r *recorder) reset() {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.recs = nil
+}
+
+func parseEntry(p []byte) (Entry, error) {
+	d := json.NewDecoder(bytes.NewReader(p))
+	d.UseNumber()
+	var fields map[string]any
+	if err := d.Decode(&fields); err != nil {
+		return Entry{}, err
+	}
+
+	e := Entry{Level: gclog.NoLevel, Fields: make(map[string]any, len(fields))}
+	for k, v := range fields {
+		switch k {
+		case "time":
+			if s, ok := v.(string); ok {
+				e.Time, _ = time.Parse(time.RFC3339Nano, s)
+			}
+		case levelKey:
+			if s, ok := v.(string); ok {
+				if lvl, err := gclog.ParseLevel(s); err == nil {
+					e.Level = lvl
+				}
+			}
+		case loggerKey:
+			e.Logger, _ = v.(string)
+		case msgKey:
+			e.Message, _ = v.(string)
+		default:
+			e.Fields[k] = typed(v)
+		}
+	}
+	return e, nil
+}
+
+// typed converts the JSON numbers to int64, uint64 or float64.
+func typed(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		for i := range v {
+			v[i] = typed(v[i])
+		}
+		return v
+	case map[string]any:
+		for k := range v {
+			v[k] = typed(v[k])
+		}
+		return v
+	}
+	return v
+}
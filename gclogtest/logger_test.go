@@ -0,0 +1,99 @@
+package gclogtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/arafath-mk/gclog"
+)
+
+func TestEntryHas(t *testing.T) {
+	l := New(t)
+	l.Info().
+		Int("int", 42).
+		Uint64("max", math.MaxUint64).
+		Float64("whole", 2.0).
+		Float64("frac", 2.5).
+		Dur("dur", 2*time.Second).
+		Time("at", FixedTime).
+		Strs("tags", []string{"a", "b"}).
+		Ints("ids", []int{1, 2}).
+		Strs("none", nil).
+		Bytes("raw", []byte("xyz")).
+		Bool("ok", true).
+		Dict("req", func(d *gclog.Line) { d.Str("method", "GET").Int("status", 200) }).
+		Msg("hi")
+
+	es := l.Entries()
+	es.AssertLen(t, 1)
+	e := es[0]
+	tests := []struct {
+		key  string
+		val  any
+		want bool
+	}{
+		{"int", 42, true},
+		{"int", int8(42), true},
+		{"int", uint(42), true},
+		{"int", 42.0, true},
+		{"int", 43, false},
+		{"int", "42", false},
+		{"max", uint64(math.MaxUint64), true},
+		{"max", -1, false},
+		{"whole", 2.0, true},
+		{"whole", 2, true},
+		{"whole", float32(2), true},
+		{"frac", 2.5, true},
+		{"frac", 2, false},
+		{"dur", 2 * time.Second, true},
+		{"dur", time.Second, false},
+		{"at", FixedTime, true},
+		{"at", FixedTime.Add(time.Second), false},
+		{"tags", []string{"a", "b"}, true},
+		{"tags", []string{"a"}, false},
+		{"tags", []any{"a", "b"}, true},
+		{"ids", []int{1, 2}, true},
+		{"ids", []int64{1, 2}, true},
+		{"ids", [2]uint{1, 2}, true},
+		{"none", []string{}, true},
+		{"raw", []byte("xyz"), true},
+		{"ok", true, true},
+		{"req", map[string]any{"method": "GET", "status": 200}, true},
+		{"req", map[string]any{"method": "GET"}, false},
+		{"missing", nil, false},
+	}
+	for _, tt := range tests {
+		if got := e.Has(tt.key, tt.val); got != tt.want {
+			t.Errorf("Has(%q, %#v) = %v, want %v. Fields: %#v", tt.key, tt.val, got, tt.want, e.Fields[tt.key])
+		}
+	}
+}
+
+func TestEntries(t *testing.T) {
+	l := New(nil)
+	l.Info().Int("user_id", 42).Msg("login")
+	l.Named("db").Warn().Msg("slow")
+	l.Print("plain")
+
+	es := l.Entries()
+	es.AssertLen(t, 3)
+	es.Level(gclog.InfoLevel).Field("user_id", 42).Message("login").AssertLen(t, 1)
+	es.Logger("db").Level(gclog.WarnLevel).AssertLen(t, 1)
+	es.Level(gclog.NoLevel).Message("plain").AssertLen(t, 1)
+	if !es[0].Time.Equal(FixedTime) {
+		t.Errorf("Time = %v, want %v", es[0].Time, FixedTime)
+	}
+
+	l.Reset()
+	l.Entries().AssertLen(t, 0)
+}
+
+func TestNewIgnoresLevelEnv(t *testing.T) {
+	t.Setenv(gclog.LevelEnv, "error,db=error")
+
+	l := New(nil)
+	l.Debug().Msg("root")
+	l.Named("db").Debug().Msg("db")
+	l.Entries().AssertLen(t, 2)
+}
@@ -1,6 +1,6 @@
 module github.com/arafath-mk/gclog
 
-go 1.19
+go 1.21
 
 require github.com/arafath-mk/gcstyle v0.1.0
 
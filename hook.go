@@ -0,0 +1,23 @@
+package gclog
+
+// Hook is run for every line before it is written. It can add fields to
+// the line, drop it with line.Discard(), or just count it.
+// msg is empty if the line is finished without Msg.
+type Hook interface {
+	Run(line *Line, lvl Level, msg string)
+}
+
+type HookFunc func(line *Line, lvl Level, msg string)
+
+func (f HookFunc) Run(line *Line, lvl Level, msg string) {
+	f(line, lvl, msg)
+}
+
+// AddHook adds the hook to the logger. Children created after this call inherit the hook.
+func (l *Logger) AddHook(h Hook) *Logger {
+	// Copy, so that the hooks of the parent and the other children are not changed.
+	hooks := make([]Hook, 0, len(l.hooks)+1)
+	hooks = append(hooks, l.hooks...)
+	l.hooks = append(hooks, h)
+	return l
+}
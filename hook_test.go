@@ -0,0 +1,37 @@
+package gclog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHooksSeePrintLines(t *testing.T) {
+	var b bytes.Buffer
+	var msgs []string
+	l := New(&b, true)
+	l.SetCaller(&CallerConfig{})
+	l.AddHook(HookFunc(func(line *Line, lvl Level, msg string) {
+		msgs = append(msgs, lvl.String()+":"+msg)
+		line.Str("hook", "ran")
+	}))
+
+	l.Print("a")
+	l.Logf("b %d", 1)
+	l.Error("c")
+	l.Info().Msg("d")
+
+	want := []string{":a", ":b 1", "error:c", "info:d"}
+	if strings.Join(msgs, ",") != strings.Join(want, ",") {
+		t.Fatalf("hook messages = %v, want %v", msgs, want)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4:\n%s", len(lines), b.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, `"hook":"ran"`) || !strings.Contains(line, "hook_test.go:") {
+			t.Errorf("line has no hook or caller field: %s", line)
+		}
+	}
+}
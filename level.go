@@ -0,0 +1,51 @@
+package gclog
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Level int8
+
+const (
+	TraceLevel Level = iota
+	DebugLevel
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+	FatalLevel
+	// NoLevel is used by Print, Log and StartJson. Lines with NoLevel do not get a "level" field.
+	NoLevel
+)
+
+const levelKey = "level"
+
+var levelNames = [...]string{
+	TraceLevel: "trace",
+	DebugLevel: "debug",
+	InfoLevel:  "info",
+	WarnLevel:  "warn",
+	ErrorLevel: "error",
+	FatalLevel: "fatal",
+	NoLevel:    "",
+}
+
+func (lvl Level) String() string {
+	if lvl < TraceLevel || lvl > NoLevel {
+		return ""
+	}
+	return levelNames[lvl]
+}
+
+func ParseLevel(str string) (Level, error) {
+	str = strings.ToLower(strings.TrimSpace(str))
+	for lvl, name := range levelNames {
+		if name == str && Level(lvl) != NoLevel {
+			return Level(lvl), nil
+		}
+	}
+	if str == "warning" {
+		return WarnLevel, nil
+	}
+	return NoLevel, fmt.Errorf("gclog: unknown level %q", str)
+}
@@ -0,0 +1,109 @@
+package gclog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LevelEnv is read by New. Format: "info,billing=debug,db=warn".
+// An entry without a name sets the minimum level of the root logger.
+// Invalid entries are skipped, and reported once to stderr.
+const LevelEnv = "GCLOG_LEVEL"
+
+const loggerKey = "logger"
+
+const unsetLevel int32 = -1
+
+// An invalid LevelEnv is reported once to levelEnvErrOut. Not for every New.
+var levelEnvErrOnce sync.Once
+var levelEnvErrOut io.Writer = os.Stderr
+
+func reportLevelEnvErr(err error) {
+	levelEnvErrOnce.Do(func() {
+		fmt.Fprintf(levelEnvErrOut, "%s: %v\n", LevelEnv, err)
+	})
+}
+
+// Named returns a child logger for the named component. Names of nested
+// children are joined with a ".". Eg: "billing.stripe".
+// A level set for "billing" also applies to "billing.stripe", unless
+// "billing.stripe" has its own level.
+func (l *Logger) Named(name string) *Logger {
+	newChild := l.newChild()
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	newChild.name = name
+
+	nameLevels := make([]*atomic.Int32, 0, len(l.nameLevels)+1)
+	nameLevels = append(nameLevels, l.w.namedLevel(name))
+	newChild.nameLevels = append(nameLevels, l.nameLevels...)
+	return newChild
+}
+
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// SetNamedLevel sets the minimum level of the named loggers. It is safe to call while logging.
+func (l *Logger) SetNamedLevel(name string, lvl Level) {
+	l.w.namedLevel(name).Store(int32(lvl))
+}
+
+// ResetNamedLevel removes the level set for the name. The named loggers will use the level of their parent.
+func (l *Logger) ResetNamedLevel(name string) {
+	l.w.namedLevel(name).Store(unsetLevel)
+}
+
+// SetLevels sets the root and named levels from a spec like "info,billing=debug,db=warn".
+// Valid entries are applied even if the spec has invalid entries.
+func (l *Logger) SetLevels(spec string) error {
+	var errs []string
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, lvlStr, named := strings.Cut(entry, "=")
+		if !named {
+			lvlStr = name
+		}
+		lvl, err := ParseLevel(lvlStr)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		if !named {
+			l.SetLevel(lvl)
+		} else {
+			l.SetNamedLevel(strings.TrimSpace(name), lvl)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("gclog: invalid level spec %q: %s", spec, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (w *Writer) namedLevel(name string) *atomic.Int32 {
+	w.namedLevelsMut.Lock()
+	defer w.namedLevelsMut.Unlock()
+
+	if w.namedLevels == nil {
+		w.namedLevels = make(map[string]*atomic.Int32)
+	}
+	nameLevel, ok := w.namedLevels[name]
+	if !ok {
+		nameLevel = &atomic.Int32{}
+		nameLevel.Store(unsetLevel)
+		w.namedLevels[name] = nameLevel
+	}
+	return nameLevel
+}
@@ -0,0 +1,96 @@
+package gclog
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSetLevels(t *testing.T) {
+	tests := []struct {
+		spec    string
+		root    Level
+		billing Level
+		db      Level
+		wantErr bool
+	}{
+		{"info", InfoLevel, InfoLevel, InfoLevel, false},
+		{"warn,billing=debug", WarnLevel, DebugLevel, WarnLevel, false},
+		{" error , billing = trace , db=WARNING ", ErrorLevel, TraceLevel, WarnLevel, false},
+		{"billing=debug,,db=error", TraceLevel, DebugLevel, ErrorLevel, false},
+		// Valid entries are applied even if the spec has invalid entries.
+		{"info,billing=dbug,db=warn", InfoLevel, InfoLevel, WarnLevel, true},
+		{"verbose", TraceLevel, TraceLevel, TraceLevel, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			l := New(io.Discard, true)
+			err := l.SetLevels(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetLevels(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got := l.GetLevel(); got != tt.root {
+				t.Errorf("root level = %v, want %v", got, tt.root)
+			}
+			if got := l.Named("billing").GetLevel(); got != tt.billing {
+				t.Errorf("billing level = %v, want %v", got, tt.billing)
+			}
+			if got := l.Named("db").GetLevel(); got != tt.db {
+				t.Errorf("db level = %v, want %v", got, tt.db)
+			}
+		})
+	}
+}
+
+func TestNamedLevelInheritance(t *testing.T) {
+	l := New(io.Discard, true)
+	l.SetLevel(InfoLevel)
+	billing := l.Named("billing")
+	stripe := billing.Named("stripe")
+	with := stripe.With().Str("k", "v").Logger()
+	if stripe.Name() != "billing.stripe" || with.Name() != "billing.stripe" {
+		t.Fatalf("names = %q, %q, want billing.stripe", stripe.Name(), with.Name())
+	}
+
+	check := func(step string, want ...Level) {
+		t.Helper()
+		for i, c := range []*Logger{billing, stripe, with} {
+			if got := c.GetLevel(); got != want[i] {
+				t.Errorf("%s: level of %s (%d) = %v, want %v", step, c.Name(), i, got, want[i])
+			}
+		}
+	}
+
+	l.SetNamedLevel("billing", WarnLevel)
+	check("billing=warn", WarnLevel, WarnLevel, WarnLevel)
+	l.SetNamedLevel("billing.stripe", DebugLevel)
+	check("billing.stripe=debug", WarnLevel, DebugLevel, DebugLevel)
+	l.ResetNamedLevel("billing.stripe")
+	check("reset billing.stripe", WarnLevel, WarnLevel, WarnLevel)
+	l.ResetNamedLevel("billing")
+	check("reset billing", InfoLevel, InfoLevel, InfoLevel)
+	if with.Enabled(DebugLevel) || !with.Enabled(InfoLevel) {
+		t.Errorf("Enabled does not follow the root level")
+	}
+}
+
+func TestInvalidLevelEnvIsReportedOnce(t *testing.T) {
+	var out bytes.Buffer
+	savedOut := levelEnvErrOut
+	levelEnvErrOut = &out
+	levelEnvErrOnce = sync.Once{}
+	defer func() { levelEnvErrOut = savedOut }()
+
+	t.Setenv(LevelEnv, "warn,billing=dbug")
+	l := New(io.Discard, true)
+	New(io.Discard, true)
+
+	if n := strings.Count(out.String(), "\n"); n != 1 || !strings.Contains(out.String(), `"dbug"`) {
+		t.Errorf("got %q, want one line about dbug", out.String())
+	}
+	if got := l.GetLevel(); got != WarnLevel {
+		t.Errorf("root level = %v, want the valid entry to be applied", got)
+	}
+}
@@ -1,8 +1,8 @@
 package gclog
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 	"reflect"
 	"runtime"
 	"strconv"
@@ -16,6 +16,10 @@ var CallStackDepthToPrint = 2
 const buffSize = 500
 const msgKey = "msg"
 
+// Suffix of the key of the field written by Line.Interface and Line.RawJSON if the value
+// can not be written as JSON. Eg: "user_err".
+const interfaceErrSuffix = "_err"
+
 var linePool = &sync.Pool{
 	New: func() interface{} {
 		return &Line{
@@ -24,310 +28,436 @@ var linePool = &sync.Pool{
 	},
 }
 
+// Line is a single log line being built. A nil *Line is a disabled line:
+// All its methods are no-op, so lines below the minimum level cost nothing.
 type Line struct {
 	log         *Logger
 	canColorize bool
-	json        bool
+	enc         Encoder
+	arrayDepth  int  // Number of open arrays. Objects in an array are written in the array.
+	arrayStart  int  // Offset of the outermost open array in buff.
+	inArray     bool // Values are elements of an array. Ie: not fields of an object in an array.
+	level       Level
+	discarded   bool
+	plainMsg    bool // The message is written with Encoder.AppendMessage. Eg: by Logger.Print.
+	timestamp   time.Time
+	hasTime     bool           // timestamp is used instead of the clock of the logger. A zero timestamp is not written.
+	stack       bool           // Err writes the stack.
+	stacks      []pendingStack // Stacks written after the other fields. See appendStackField.
+	keyPrefix   string         // Keys of the flattened parent objects, joined with ".". Ends with "." if not empty.
+	fieldsStart int            // Offset of the first field of the current object in buff.
 	buff        []byte
 }
 
-func newLine(log *Logger, colorize bool, json bool) *Line {
+func newLine(log *Logger, colorize bool, enc Encoder) *Line {
 	l := linePool.Get().(*Line)
 	l.buff = l.buff[:0]
 	l.log = log
 	l.canColorize = colorize
-	l.json = json
+	l.enc = enc
+	l.arrayDepth = 0
+	l.arrayStart = 0
+	l.inArray = false
+	l.level = NoLevel
+	l.discarded = false
+	l.plainMsg = false
+	l.timestamp = time.Time{}
+	l.hasTime = false
+	l.stack = false
+	clear(l.stacks)
+	l.stacks = l.stacks[:0]
+	l.keyPrefix = ""
+	l.fieldsStart = 0
 	return l
 }
 
 func (l *Line) Logger() *Logger {
+	if l == nil {
+		return nil
+	}
+
 	return l.log
 }
 
 func (l *Line) Finish() {
-	if len(l.buff) > 2 {
-		l.log.print(l.buff[2:]) // l.buff[2:] -> No need to print the ", " at the start.
+	if l == nil {
+		return
+	}
+
+	l.finish("", false)
+}
+
+func (l *Line) finish(msg string, hasMsg bool) {
+	// The clock is read once, for both the sampler and the timestamp. So, a fake
+	// clock which moves a step on every read (See gclogtest) moves a step per line.
+	var now time.Time
+	if l.log != nil && (l.log.sampler != nil || !l.hasTime) {
+		now = l.log.now()
+	}
+
+	if l.log != nil && l.log.sampler != nil {
+		if ok, dropped := l.log.sampler.sample(l.level, msg, now); !ok {
+			l.discarded = true
+		} else if dropped > 0 {
+			l.Uint64(sampledDroppedKey, dropped)
+		}
+	}
+	if l.log != nil && l.log.caller != nil && !l.discarded {
+		l.appendCaller(l.log.caller)
+	}
+	if l.log != nil && !l.discarded {
+		for _, h := range l.log.hooks {
+			h.Run(l, l.level, msg)
+		}
+	}
+	if hasMsg && !l.discarded {
+		if l.plainMsg {
+			if len(l.buff) > 0 {
+				l.buff = l.enc.AppendDelim(l.buff)
+			}
+			l.buff = l.enc.AppendMessage(l.buff, msg)
+		} else {
+			l.Str(msgKey, msg)
+		}
+	}
+	if !l.discarded {
+		l.appendPendingStacks()
+	}
+
+	// A plain message line is written even if it is empty. Eg: Println().
+	if (len(l.buff) > 0 || l.plainMsg) && !l.discarded {
+		t := l.timestamp
+		if !l.hasTime {
+			t = now
+		}
+		l.log.print(l.level, t, l.buff)
 	}
+	exit := l.level == FatalLevel
+	log := l.log
 	linePool.Put(l)
+	if exit {
+		if log != nil {
+			_ = log.Sync()
+		}
+		os.Exit(1)
+	}
+}
+
+// Discard drops the line. It is meant to be used by hooks.
+func (l *Line) Discard() {
+	if l == nil {
+		return
+	}
+
+	l.discarded = true
 }
 
 func (l *Line) Send() {
+	if l == nil {
+		return
+	}
+
 	l.Finish()
 }
 
 func (l *Line) Int(key string, val int) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendInt(int64(val))
 	return l
 }
 
 func (l *Line) Ints(key string, val []int) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendInt(int64(val[0]))
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendInt(int64(val[i]))
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v int) { l.appendInt(int64(v)) })
 	return l
 }
 
 func (l *Line) Int8(key string, val int8) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendInt(int64(val))
 	return l
 }
 
 func (l *Line) Ints8(key string, val []int8) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendInt(int64(val[0]))
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendInt(int64(val[i]))
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v int8) { l.appendInt(int64(v)) })
 	return l
 }
 
 func (l *Line) Int16(key string, val int16) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendInt(int64(val))
 	return l
 }
 
 func (l *Line) Ints16(key string, val []int16) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendInt(int64(val[0]))
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendInt(int64(val[i]))
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v int16) { l.appendInt(int64(v)) })
 	return l
 }
 
 func (l *Line) Int32(key string, val int32) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendInt(int64(val))
 	return l
 }
 
 func (l *Line) Ints32(key string, val []int32) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendInt(int64(val[0]))
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendInt(int64(val[i]))
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v int32) { l.appendInt(int64(v)) })
 	return l
 }
 
 func (l *Line) Int64(key string, val int64) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendInt(int64(val))
 	return l
 }
 
 func (l *Line) Ints64(key string, val []int64) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendInt(int64(val[0]))
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendInt(int64(val[i]))
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v int64) { l.appendInt(int64(v)) })
 	return l
 }
 
 func (l *Line) Uint(key string, val uint) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendUInt(uint64(val))
 	return l
 }
 
 func (l *Line) Uints(key string, val []uint) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendUInt(uint64(val[0]))
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendUInt(uint64(val[i]))
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v uint) { l.appendUInt(uint64(v)) })
 	return l
 }
 
 func (l *Line) Uint8(key string, val uint8) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendUInt(uint64(val))
 	return l
 }
 
 func (l *Line) Uints8(key string, val []uint8) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendUInt(uint64(val[0]))
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendUInt(uint64(val[i]))
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v uint8) { l.appendUInt(uint64(v)) })
 	return l
 }
 
 func (l *Line) Uint16(key string, val uint16) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendUInt(uint64(val))
 	return l
 }
 
 func (l *Line) Uints16(key string, val []uint16) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendUInt(uint64(val[0]))
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendUInt(uint64(val[i]))
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v uint16) { l.appendUInt(uint64(v)) })
 	return l
 }
 func (l *Line) Uint32(key string, val uint32) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendUInt(uint64(val))
 	return l
 }
 
 func (l *Line) Uints32(key string, val []uint32) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendUInt(uint64(val[0]))
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendUInt(uint64(val[i]))
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v uint32) { l.appendUInt(uint64(v)) })
 	return l
 }
 
 func (l *Line) Uint64(key string, val uint64) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendUInt(uint64(val))
 	return l
 }
 
 func (l *Line) Uints64(key string, val []uint64) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendUInt(uint64(val[0]))
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendUInt(uint64(val[i]))
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v uint64) { l.appendUInt(uint64(v)) })
 	return l
 }
 
 func (l *Line) Bytes(key string, val []byte) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendStr(string(val))
 	return l
 }
 
 func (l *Line) Str(key string, val string) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendStr(val)
 	return l
 }
 
 func (l *Line) Strs(key string, val []string) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendStr(val[0])
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendStr(val[i])
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v string) { l.appendStr(v) })
 	return l
 }
 
 func (l *Line) Bool(key string, val bool) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendBool(val)
 	return l
 }
 
 func (l *Line) Bools(key string, val []bool) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendBool(val[0])
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendBool(val[i])
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v bool) { l.appendBool(v) })
 	return l
 }
 
 func (l *Line) Msg(msg string) {
-	l.Str(msgKey, msg)
-	l.Finish()
+	if l == nil {
+		return
+	}
+
+	l.finish(msg, true)
 }
 
 func (l *Line) Msgf(f string, v ...any) {
+	if l == nil {
+		return
+	}
+
 	l.Msg(fmt.Sprintf(f, v...))
 }
 
 func (l *Line) Float32(key string, val float32) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendFloat(float64(val), 32)
 	return l
 }
 
 func (l *Line) Floats32(key string, val []float32) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendFloat(float64(val[0]), 32)
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendFloat(float64(val[i]), 32)
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v float32) { l.appendFloat(float64(v), 32) })
 	return l
 }
 
 func (l *Line) Float64(key string, val float64) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendFloat(val, 64)
 	return l
 }
 
 func (l *Line) Floats64(key string, val []float64) *Line {
-	l.appendKey(key)
-	l.buff = append(l.buff, '[')
-	l.appendFloat(val[0], 64)
-	for i := 1; i < len(val); i++ {
-		l.buff = append(l.buff, ',', ' ')
-		l.appendFloat(val[i], 64)
+	if l == nil {
+		return l
 	}
-	l.buff = append(l.buff, ']')
+
+	appendSlice(l, key, val, func(l *Line, v float64) { l.appendFloat(v, 64) })
 	return l
 }
 
 // -->
 func (l *Line) Err(err error) *Line {
-	if err == nil {
+	if l == nil || err == nil {
 		return l
 	}
 
-	l.appendKey("err")
+	l.appendKey(errKey)
 	l.appendStr(err.Error())
 	if PrintCallStackForErr {
 		var file string
@@ -341,41 +471,70 @@ func (l *Line) Err(err error) *Line {
 			}
 		}
 		l.appendKey("errLoggedFrom")
-		l.buff = append(l.buff, '"')
-		l.buff = append(l.buff, file...)
-		l.buff = append(l.buff, ':')
-		l.buff = strconv.AppendInt(l.buff, int64(line), 10)
-		l.buff = append(l.buff, '"')
+		l.appendStr(file + ":" + strconv.Itoa(line))
 	}
+	if l.stack || (l.log != nil && l.log.errStack) {
+		l.appendStack(err)
+		l.stack = false
+	}
+	l.appendErrChain(err)
 
 	return l
 }
 
 func (l *Line) Time(key string, val time.Time) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendTime(val)
 	return l
 }
 
 func (l *Line) Dur(key string, val time.Duration) *Line {
-	l.appendKey(key)
-	if !l.json {
-		l.appendStr(fmt.Sprintf("%v", val))
-	} else {
-		l.appendStr(fmt.Sprintf("%d", val))
+	if l == nil {
+		return l
 	}
+
+	l.appendKey(key)
+	l.appendDur(val)
 	return l
 }
 
 func (l *Line) Interface(key string, val any) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
-	// l.appendStr(fmt.Sprintf("%v", val))
-	b, _ := json.Marshal(val)
-	l.appendStr(string(b))
+	if err := l.appendInterface(val); err != nil {
+		l.appendKey(key + interfaceErrSuffix)
+		l.appendErrStr(err.Error())
+	}
+	return l
+}
+
+// RawJSON writes val, which is JSON, compacted. An empty val is written as null in JSON.
+// If val is not valid JSON, it is written as a string, with the error in the field key+"_err".
+func (l *Line) RawJSON(key string, val []byte) *Line {
+	if l == nil {
+		return l
+	}
+
+	l.appendKey(key)
+	if err := l.appendRawJSON(val); err != nil {
+		l.appendKey(key + interfaceErrSuffix)
+		l.appendErrStr(err.Error())
+	}
 	return l
 }
 
 func (l *Line) Type(key string, val any) *Line {
+	if l == nil {
+		return l
+	}
+
 	l.appendKey(key)
 	l.appendStr(reflect.TypeOf(val).String())
 	return l
@@ -1,8 +1,9 @@
 package gclog
 
 import (
+	"encoding/json"
+	"fmt"
 	"math"
-	"strconv"
 	"time"
 )
 
@@ -15,31 +16,45 @@ var styleValEnd = styleVal.End(true)
 var styleValErrStart = styleValErr.Start(true)
 var styleValErrEnd = styleValErr.End(true)
 
+var styleLevelStarts, styleLevelEnds = levelStyleCodes()
+
+func levelStyleCodes() (starts, ends [len(styleLevels)]string) {
+	for i, s := range styleLevels {
+		starts[i] = s.Start(true)
+		ends[i] = s.End(true)
+	}
+	return starts, ends
+}
+
+// appendKey writes the delimiter (if it is not the first field) and the key.
 func (l *Line) appendKey(key string) {
-	if !l.json {
-		l.buff = append(l.buff, ',', ' ')
-	} else {
-		l.buff = append(l.buff, ',', ' ', '"')
+	if len(l.buff) > l.fieldsStart {
+		l.buff = l.enc.AppendDelim(l.buff)
+	}
+	if l.keyPrefix != "" {
+		key = l.keyPrefix + key
 	}
+	l.appendFirstKey(key)
+}
 
+// appendFirstKey is same as appendKey. But, it does not write the delimiter.
+func (l *Line) appendFirstKey(key string) {
 	if l.canColorize {
 		l.buff = append(l.buff, styleKeyStart...)
 	}
-
-	if !l.json {
-		l.buff = append(l.buff, key...)
-	} else {
-		l.appendSafeString(key)
-	}
-
+	l.buff = l.enc.AppendKey(l.buff, key)
 	if l.canColorize {
 		l.buff = append(l.buff, styleKeyEnd...)
 	}
+}
 
-	if !l.json {
-		l.buff = append(l.buff, '=')
-	} else {
-		l.buff = append(l.buff, '"', ':')
+func (l *Line) appendLevel(lvl Level) {
+	if l.canColorize {
+		l.buff = append(l.buff, styleLevelStarts[lvl]...)
+	}
+	l.buff = l.enc.AppendLevel(l.buff, lvl)
+	if l.canColorize {
+		l.buff = append(l.buff, styleLevelEnds[lvl]...)
 	}
 }
 
@@ -47,7 +62,7 @@ func (l *Line) appendInt(val int64) {
 	if l.canColorize {
 		l.buff = append(l.buff, styleValStart...)
 	}
-	l.buff = strconv.AppendInt(l.buff, val, 10)
+	l.buff = l.enc.AppendInt(l.buff, val)
 	if l.canColorize {
 		l.buff = append(l.buff, styleValEnd...)
 	}
@@ -57,95 +72,199 @@ func (l *Line) appendUInt(val uint64) {
 	if l.canColorize {
 		l.buff = append(l.buff, styleValStart...)
 	}
-	l.buff = strconv.AppendUint(l.buff, val, 10)
+	l.buff = l.enc.AppendUint(l.buff, val)
 	if l.canColorize {
 		l.buff = append(l.buff, styleValEnd...)
 	}
 }
 
 func (l *Line) appendStr(val string) {
-	l.buff = append(l.buff, '"')
 	if l.canColorize {
 		l.buff = append(l.buff, styleValStart...)
 	}
-
-	if !l.json {
-		l.buff = append(l.buff, val...)
+	if l.inArray {
+		l.buff = l.enc.AppendArrayString(l.buff, val)
 	} else {
-		l.appendSafeString(val)
+		l.buff = l.enc.AppendString(l.buff, val)
 	}
-
 	if l.canColorize {
 		l.buff = append(l.buff, styleValEnd...)
 	}
-	l.buff = append(l.buff, '"')
 }
 
 func (l *Line) appendBool(val bool) {
 	if l.canColorize {
 		l.buff = append(l.buff, styleValStart...)
 	}
-	if val {
-		l.buff = append(l.buff, 't', 'r', 'u', 'e')
-	} else {
-		l.buff = append(l.buff, 'f', 'a', 'l', 's', 'e')
+	l.buff = l.enc.AppendBool(l.buff, val)
+	if l.canColorize {
+		l.buff = append(l.buff, styleValEnd...)
 	}
+}
+
+func (l *Line) appendFloat(val float64, bitSize int) {
+	// NaN and ∞ are colorized as errors.
+	if l.canColorize && (math.IsNaN(val) || math.IsInf(val, 0)) {
+		l.buff = append(l.buff, styleValErrStart...)
+		l.buff = l.enc.AppendFloat(l.buff, val, bitSize)
+		l.buff = append(l.buff, styleValErrEnd...)
+		return
+	}
+
+	if l.canColorize {
+		l.buff = append(l.buff, styleValStart...)
+	}
+	l.buff = l.enc.AppendFloat(l.buff, val, bitSize)
 	if l.canColorize {
 		l.buff = append(l.buff, styleValEnd...)
 	}
 }
 
-var floatErrStrings = []string{"null", "'NaN'", "'-∞'", "'∞'"}
+func (l *Line) appendTime(val time.Time) {
+	if l.canColorize {
+		l.buff = append(l.buff, styleValStart...)
+	}
+	l.buff = l.enc.AppendTime(l.buff, val)
+	if l.canColorize {
+		l.buff = append(l.buff, styleValEnd...)
+	}
+}
 
-func (l *Line) appendFloat(val float64, bitSize int) {
-	// Error case.
-	i := 0
-	floatErr := false
-	switch {
-	case math.IsNaN(val):
-		floatErr = true
-		if !l.json {
-			i = 1
-		}
-	case math.IsInf(val, -1):
-		floatErr = true
-		if !l.json {
-			i = 2
-		}
-	case math.IsInf(val, 1):
-		floatErr = true
-		if !l.json {
-			i = 3
-		}
+func (l *Line) appendDur(val time.Duration) {
+	if l.canColorize {
+		l.buff = append(l.buff, styleValStart...)
+	}
+	l.buff = l.enc.AppendDuration(l.buff, val)
+	if l.canColorize {
+		l.buff = append(l.buff, styleValEnd...)
 	}
+}
 
-	if floatErr {
-		if l.canColorize {
-			l.buff = append(l.buff, styleValErrStart...)
-		}
-		l.buff = append(l.buff, floatErrStrings[i]...)
-		if l.canColorize {
-			l.buff = append(l.buff, styleValErrEnd...)
+// appendSlice writes the key and the slice, as per the SliceConfig of the logger.
+func appendSlice[T any](l *Line, key string, val []T, appendVal func(l *Line, v T)) {
+	c := l.sliceConfig()
+	l.appendKey(key)
+	if val == nil && c.NilAsNull {
+		l.appendNil()
+		return
+	}
+
+	n := len(val)
+	if c.MaxLen > 0 && n > c.MaxLen {
+		n = c.MaxLen
+	}
+	inArray := l.appendArrayStart()
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			l.appendArrayDelim()
 		}
+		appendVal(l, val[i])
+	}
+	l.appendArrayEnd(inArray)
+
+	// The number of the rest is a separate field. So, an array of numbers has only numbers.
+	if more := len(val) - n; more > 0 {
+		l.appendKey(key + sliceMoreSuffix)
+		l.appendInt(int64(more))
+	}
+}
+
+func (l *Line) appendNil() {
+	ne, ok := l.enc.(NilEncoder)
+	if !ok {
+		inArray := l.appendArrayStart()
+		l.appendArrayEnd(inArray)
 		return
 	}
 
-	// Normal case.
 	if l.canColorize {
 		l.buff = append(l.buff, styleValStart...)
 	}
-	l.buff = strconv.AppendFloat(l.buff, val, 'f', -1, bitSize)
+	l.buff = ne.AppendNil(l.buff)
 	if l.canColorize {
 		l.buff = append(l.buff, styleValEnd...)
 	}
 }
 
-func (l *Line) appendTime(val time.Time) {
+// appendArrayStart returns the previous inArray. Pass it to appendArrayEnd.
+func (l *Line) appendArrayStart() (inArray bool) {
+	inArray = l.inArray
+	l.inArray = true
+	if l.arrayDepth == 0 {
+		l.arrayStart = len(l.buff)
+	}
+	l.buff = l.enc.AppendArrayStart(l.buff)
+	l.arrayDepth++
+	return inArray
+}
+
+func (l *Line) appendArrayDelim() {
+	l.buff = l.enc.AppendArrayDelim(l.buff)
+}
+
+func (l *Line) appendArrayEnd(inArray bool) {
+	l.inArray = inArray
+	l.arrayDepth--
+	l.buff = l.enc.AppendArrayEnd(l.buff)
+	if l.arrayDepth == 0 {
+		if q, ok := l.enc.(ArrayQuoter); ok {
+			l.buff = q.QuoteArray(l.buff, l.arrayStart)
+		}
+	}
+}
+
+// appendInterface writes val as JSON, if the encoder is a RawJSONEncoder. Otherwise, in %+v form.
+// If val can not be marshalled, it writes null and returns the error.
+func (l *Line) appendInterface(val any) error {
+	if _, ok := l.enc.(RawJSONEncoder); !ok {
+		l.appendStr(fmt.Sprintf("%+v", val))
+		return nil
+	}
+
+	b, err := json.Marshal(val)
+	if err != nil {
+		b = nil // Written as null.
+	}
+	if rawErr := l.appendRawJSON(b); err == nil {
+		err = rawErr
+	}
+	return err
+}
+
+// appendRawJSON writes val as JSON, if the encoder is a RawJSONEncoder. Otherwise, as a string.
+// If val is not valid JSON, it writes val as a string and returns the error.
+func (l *Line) appendRawJSON(val []byte) error {
+	re, ok := l.enc.(RawJSONEncoder)
+	if !ok {
+		l.appendStr(string(val))
+		return nil
+	}
+
 	if l.canColorize {
 		l.buff = append(l.buff, styleValStart...)
 	}
-	l.buff = val.AppendFormat(l.buff, "2006/01/02 15:04:05")
+	var err error
+	l.buff, err = re.AppendRawJSON(l.buff, val)
+	if err != nil {
+		l.buff = l.enc.AppendString(l.buff, string(val))
+	}
 	if l.canColorize {
 		l.buff = append(l.buff, styleValEnd...)
 	}
+	return err
+}
+
+// appendErrStr is same as appendStr. But, it is colorized as an error.
+func (l *Line) appendErrStr(val string) {
+	if l.canColorize {
+		l.buff = append(l.buff, styleValErrStart...)
+	}
+	if l.inArray {
+		l.buff = l.enc.AppendArrayString(l.buff, val)
+	} else {
+		l.buff = l.enc.AppendString(l.buff, val)
+	}
+	if l.canColorize {
+		l.buff = append(l.buff, styleValErrEnd...)
+	}
 }
@@ -20,3 +20,13 @@ var styleVal = gcstyle.Style{
 var styleValErr = gcstyle.Style{
 	Color: &wcolor.Red,
 }
+
+var styleLevels = [...]gcstyle.Style{
+	TraceLevel: {Color: wcolor.Grey.Clone()},
+	DebugLevel: {Color: wcolor.Blue.Clone()},
+	InfoLevel:  {Color: wcolor.Green.Clone()},
+	WarnLevel:  {Color: wcolor.Yellow.Clone()},
+	ErrorLevel: {Color: &wcolor.Red},
+	FatalLevel: {Color: &wcolor.Red, Bold: true},
+	NoLevel:    {},
+}
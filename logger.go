@@ -3,54 +3,79 @@ package gclog
 import (
 	"fmt"
 	"io"
-	"strconv"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/arafath-mk/gcstyle"
-	"github.com/arafath-mk/gcstyle/wcolor"
 )
 
-type Writer struct {
-	out io.Writer
-	mut sync.Mutex
-}
-
-func (w *Writer) Write(data []byte) {
-	w.mut.Lock()
-	defer w.mut.Unlock()
-
-	w.out.Write(data)
-}
-
 var loggerPool = &sync.Pool{
 	New: func() interface{} {
-		l := &Logger{
-			w: &Writer{},
-		}
-		return l
+		return &Logger{}
 	},
 }
 
 type Logger struct {
-	w             *Writer // Writer is shared with children.
-	canApplyStyle bool
-	json          bool
-	finished      bool
-	context       *Line
+	w              *Writer // Writer is shared with children.
+	canApplyStyle  bool
+	enc            Encoder
+	finished       bool
+	context        *Line
+	name           string
+	nameLevels     []*atomic.Int32 // Levels of name and its parents. Most specific first.
+	hooks          []Hook
+	sampler        *Sampler
+	clock          Clock
+	caller         *CallerConfig
+	errStack       bool
+	slices         SliceConfig
+	traceFields    TraceFields
+	traceExtractor TraceExtractor
 }
 
 func New(w io.Writer, json bool) *Logger {
+	if json {
+		return NewWithEncoder(w, JsonEncoder{})
+	}
+	return NewWithEncoder(w, TextEncoder{})
+}
+
+// NewLogfmt returns a logger which writes lines in logfmt format. Lines are never colorized.
+func NewLogfmt(w io.Writer) *Logger {
+	return NewWithEncoder(w, LogfmtEncoder{})
+}
+
+// NewWithEncoder returns a logger which writes lines in the format of enc.
+func NewWithEncoder(w io.Writer, enc Encoder) *Logger {
 	if w == nil {
 		w = io.Discard
 	}
 
 	l := loggerPool.Get().(*Logger)
-	l.w.out = w
-	l.canApplyStyle = gcstyle.CanApplyStyle(w)
-	l.json = json
+	// A pooled logger may have been a child. So, its Writer may still be in use by the parent.
+	l.w = &Writer{out: w}
+	l.w.level.Store(int32(TraceLevel))
+	l.canApplyStyle = gcstyle.CanApplyStyle(w) && enc.CanColorize()
+	l.enc = enc
 	l.finished = false
-	l.context = newLine(nil, l.canApplyStyle, l.json)
+	l.context = newLine(nil, l.canApplyStyle, l.enc)
+	l.name = ""
+	l.nameLevels = nil
+	l.hooks = nil
+	l.sampler = nil
+	l.clock = nil
+	l.caller = nil
+	l.errStack = false
+	l.slices = SliceConfig{}
+	l.traceFields = OTelTraceFields
+	l.traceExtractor = nil
+	if spec := os.Getenv(LevelEnv); spec != "" {
+		if err := l.SetLevels(spec); err != nil {
+			reportLevelEnvErr(err)
+		}
+	}
 	return l
 }
 
@@ -58,10 +83,20 @@ func (l *Logger) newChild() *Logger {
 	newChild := loggerPool.Get().(*Logger)
 	newChild.w = l.w // Writer is shared with children.
 	newChild.canApplyStyle = l.canApplyStyle
-	newChild.json = l.json
+	newChild.enc = l.enc
 	// Allows to create new child of a finished logger. But, it should not output anything.
 	newChild.finished = l.finished
-	newChild.context = newLine(newChild, l.canApplyStyle, l.json)
+	newChild.name = l.name
+	newChild.nameLevels = l.nameLevels
+	newChild.hooks = l.hooks
+	newChild.sampler = l.sampler
+	newChild.clock = l.clock
+	newChild.caller = l.caller
+	newChild.errStack = l.errStack
+	newChild.slices = l.slices
+	newChild.traceFields = l.traceFields
+	newChild.traceExtractor = l.traceExtractor
+	newChild.context = newLine(newChild, l.canApplyStyle, l.enc)
 	if !l.finished {
 		newChild.context.buff = append(newChild.context.buff, l.context.buff...)
 	}
@@ -80,8 +115,28 @@ func (l *Logger) EndWith() {
 	loggerPool.Put(l)
 }
 
+// SetLevel sets the minimum level of the logger and all its children. It is safe to call while logging.
+func (l *Logger) SetLevel(lvl Level) {
+	l.w.level.Store(int32(lvl))
+}
+
+// GetLevel returns the minimum level of the logger. A level set for the logger's name takes precedence.
+func (l *Logger) GetLevel() Level {
+	for _, nameLevel := range l.nameLevels {
+		if min := nameLevel.Load(); min != unsetLevel {
+			return Level(min)
+		}
+	}
+	return Level(l.w.level.Load())
+}
+
+func (l *Logger) Enabled(lvl Level) bool {
+	return lvl >= l.GetLevel()
+}
+
+// ForceColor has no effect if the encoder can not be colorized. Eg: LogfmtEncoder.
 func (l *Logger) ForceColor() {
-	l.canApplyStyle = true
+	l.canApplyStyle = l.enc.CanColorize()
 }
 
 func (l *Logger) CanColorize() bool {
@@ -93,83 +148,59 @@ func (l *Logger) Println(a ...any) {
 }
 
 func (l *Logger) Print(a ...any) {
-	l.print([]byte(l.msg(a...)))
+	l.printMsg(NoLevel, fmt.Sprint(a...))
 }
 
-func (l *Logger) print(msg []byte) {
-	if !l.json {
-		l.printText(msg)
-		return
-	}
-
-	l.printJson(msg)
+// printMsg finishes a line with the message, like Line.Msg. So, the hooks, sampler and caller apply to it.
+// But, the message is written with Encoder.AppendMessage. Eg: without the key in text.
+func (l *Logger) printMsg(lvl Level, msg string) {
+	line := newLine(l, false, l.enc)
+	line.level = lvl
+	line.plainMsg = true
+	line.finish(msg, true)
 }
 
-func (l *Logger) printText(msg []byte) {
+// print writes the line. fields are written after the context fields of the logger.
+// The timestamp is not written if t is zero.
+func (l *Logger) print(lvl Level, t time.Time, fields []byte) {
 	if l.finished {
 		return
 	}
 
-	var prefix []byte = l.context.buff
-	if len(l.context.buff) > 2 && l.context.buff[0] == ',' {
-		prefix = l.context.buff[2:] // buff[2:] -> No need to print the ", " at the start.
-	}
-
-	line := newLine(nil, false, l.json)
+	line := newLine(nil, l.canApplyStyle, l.enc)
 	defer linePool.Put(line)
 
-	// fmt.Sprintf("%s %s%s\n", now, l.context.buff, msg)
-	if l.canApplyStyle {
-		line.buff = append(line.buff, styleValStart...)
-	}
-	line.buff = time.Now().AppendFormat(line.buff, "2006/01/02 15:04:05")
-	if l.canApplyStyle {
-		line.buff = append(line.buff, styleValEnd...)
-	}
-	line.buff = append(line.buff, ' ')
-	line.buff = append(line.buff, prefix...)
-	if len(prefix) > 0 {
-		line.buff = append(line.buff, ',')
-		line.buff = append(line.buff, ' ')
-	}
-	line.buff = append(line.buff, msg...)
-	line.buff = append(line.buff, '\n')
-	l.w.Write(line.buff)
-}
-
-func (l *Logger) printJson(msg []byte) {
-	if l.finished {
-		return
-	}
-
-	var prefix []byte = l.context.buff
-	if len(l.context.buff) > 2 && l.context.buff[0] == ',' {
-		prefix = l.context.buff[2:] // buff[2:] -> No need to print the ", " at the start.
+	line.buff = l.enc.BeginLine(line.buff)
+	if !t.IsZero() {
+		if l.canApplyStyle {
+			line.buff = append(line.buff, styleValStart...)
+		}
+		line.buff = l.enc.AppendTimestamp(line.buff, t)
+		if l.canApplyStyle {
+			line.buff = append(line.buff, styleValEnd...)
+		}
 	}
 
-	line := newLine(nil, false, l.json)
-	defer linePool.Put(line)
-
-	t := time.Now().UnixMicro()
-	timeKey := l.ColorizeText("time", *styleKey.Color)
-	// fmt.Sprintf("{\"%s\": \"%s\", %s%s}\n", timeKey, now, prefix, msg)
-	line.buff = append(line.buff, '{', '"')
-	line.buff = append(line.buff, timeKey...)
-	line.buff = append(line.buff, '"', ':')
-	if l.canApplyStyle {
-		line.buff = append(line.buff, styleValStart...)
+	// The fields after the timestamp. Each of them is written after a delimiter, except the first one.
+	line.fieldsStart = len(line.buff)
+	if lvl != NoLevel {
+		line.appendKey(levelKey)
+		line.appendLevel(lvl)
 	}
-	line.buff = strconv.AppendInt(line.buff, t, 10)
-	if l.canApplyStyle {
-		line.buff = append(line.buff, styleValEnd...)
+	if len(l.name) > 0 {
+		line.appendKey(loggerKey)
+		line.appendStr(l.name)
 	}
-	line.buff = append(line.buff, ',', ' ')
-	line.buff = append(line.buff, prefix...)
-	if len(prefix) > 0 {
-		line.buff = append(line.buff, ',', ' ')
+	for _, part := range [2][]byte{l.context.buff, fields} {
+		if len(part) == 0 {
+			continue
+		}
+		if len(line.buff) > line.fieldsStart {
+			line.buff = l.enc.AppendDelim(line.buff)
+		}
+		line.buff = append(line.buff, part...)
 	}
-	line.buff = append(line.buff, msg...)
-	line.buff = append(line.buff, '}', '\n')
+	line.buff = l.enc.EndLine(line.buff)
 	l.w.Write(line.buff)
 }
 
@@ -181,8 +212,12 @@ func (l *Logger) Logf(format string, a ...any) {
 	l.Print(fmt.Sprintf(format, a...))
 }
 
+// Error prints the message with "error" level. Use WithLevel(ErrorLevel) for a structured error line.
 func (l *Logger) Error(a ...any) {
-	l.Println(l.ColorizeText(fmt.Sprint(a...), wcolor.Red))
+	if !l.Enabled(ErrorLevel) {
+		return
+	}
+	l.printMsg(ErrorLevel, fmt.Sprint(a...))
 }
 
 func (l *Logger) LogHttpRequest(str string) {
@@ -204,12 +239,36 @@ func (l *Logger) Out(str string) {
 }
 
 func (l *Logger) StartJson() *Line {
-	return newLine(l, false, l.json)
+	return newLine(l, false, l.enc)
 }
 
-func (l *Logger) msg(a ...any) string {
-	if !l.json {
-		return fmt.Sprint(a...)
+// WithLevel returns a nil *Line if lvl is below the minimum level.
+func (l *Logger) WithLevel(lvl Level) *Line {
+	if !l.Enabled(lvl) {
+		return nil
 	}
-	return fmt.Sprintf("\"msg\":\"%s\"", fmt.Sprint(a...))
+	line := newLine(l, false, l.enc)
+	line.level = lvl
+	return line
+}
+
+func (l *Logger) Trace() *Line {
+	return l.WithLevel(TraceLevel)
+}
+
+func (l *Logger) Debug() *Line {
+	return l.WithLevel(DebugLevel)
+}
+
+func (l *Logger) Info() *Line {
+	return l.WithLevel(InfoLevel)
+}
+
+func (l *Logger) Warn() *Line {
+	return l.WithLevel(WarnLevel)
+}
+
+// Fatal returns a line with "fatal" level. The process exits with status 1 once the line is finished.
+func (l *Logger) Fatal() *Line {
+	return l.WithLevel(FatalLevel)
 }
@@ -0,0 +1,94 @@
+package gclog
+
+// ObjectMarshaler is implemented by types which write themselves as an
+// object. MarshalLogObject writes the fields of the object to l. Eg:
+//
+//	func (u User) MarshalLogObject(l *gclog.Line) {
+//		l.Int("id", u.ID).Str("name", u.Name)
+//	}
+//
+// Then, log.Info().Object("user", u).Msg("login") writes it without reflection.
+// Pass a pointer (Eg: &u) to avoid the allocation of converting a struct to an interface.
+type ObjectMarshaler interface {
+	MarshalLogObject(l *Line)
+}
+
+type ObjectMarshalerFunc func(l *Line)
+
+func (f ObjectMarshalerFunc) MarshalLogObject(l *Line) {
+	f(l)
+}
+
+// objectState is the state of a Line which is saved while a nested object is written.
+type objectState struct {
+	keyPrefix   string
+	fieldsStart int
+	inArray     bool
+}
+
+// Dict writes a nested object with the fields written by fn. Eg:
+//
+//	log.Info().Dict("req", func(d *gclog.Line) {
+//		d.Str("method", r.Method).Str("path", r.URL.Path)
+//	}).Msg("request")
+//
+// In JSON, it is {"req":{"method":"GET", "path":"/"}}. In text and logfmt, it
+// is written as dotted keys. Eg: req.method="GET", req.path="/".
+// The fields are written to the buffer of the line. fn must not finish the line.
+func (l *Line) Dict(key string, fn func(d *Line)) *Line {
+	return l.Object(key, ObjectMarshalerFunc(fn))
+}
+
+// Object writes the object as a nested object. Like Dict, it is written as dotted keys in text and logfmt.
+func (l *Line) Object(key string, val ObjectMarshaler) *Line {
+	if l == nil {
+		return l
+	}
+
+	if _, nests := l.enc.(ObjectEncoder); !nests && l.arrayDepth == 0 {
+		prefix := l.keyPrefix
+		l.keyPrefix = prefix + key + "."
+		if val != nil {
+			val.MarshalLogObject(l)
+		}
+		l.keyPrefix = prefix
+		return l
+	}
+
+	l.appendKey(key)
+	l.appendObject(val)
+	return l
+}
+
+// appendObject writes a nested object, as a value. A nil val is written as an empty object.
+func (l *Line) appendObject(val ObjectMarshaler) {
+	saved := l.startObject()
+	if val != nil {
+		val.MarshalLogObject(l)
+	}
+	l.endObject(saved)
+}
+
+func (l *Line) startObject() objectState {
+	saved := objectState{keyPrefix: l.keyPrefix, fieldsStart: l.fieldsStart, inArray: l.inArray}
+	if oe, ok := l.enc.(ObjectEncoder); ok {
+		l.buff = oe.AppendObjectStart(l.buff)
+	} else {
+		l.buff = append(l.buff, '{')
+	}
+	l.keyPrefix = ""
+	l.fieldsStart = len(l.buff)
+	l.inArray = false
+	return saved
+}
+
+func (l *Line) endObject(saved objectState) {
+	if oe, ok := l.enc.(ObjectEncoder); ok {
+		l.buff = oe.AppendObjectEnd(l.buff)
+	} else {
+		l.buff = append(l.buff, '}')
+	}
+	l.keyPrefix = saved.keyPrefix
+	l.fieldsStart = saved.fieldsStart
+	l.inArray = saved.inArray
+}
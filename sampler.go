@@ -0,0 +1,96 @@
+package gclog
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const sampledDroppedKey = "dropped"
+
+// Number of counters per level. Lines are mapped to the counters by hash of
+// their message. So, different messages may share a counter.
+const samplerBuckets = 1024
+
+// Sampler logs the first N lines with the same level and message in every
+// interval. After that, it logs every Mth line. Rest of the lines are dropped.
+// The next logged line of the same level and message gets a "dropped" field
+// with the number of lines dropped before it.
+type Sampler struct {
+	first      uint64
+	thereafter uint64
+	tick       int64
+	dropped    atomic.Uint64
+	counters   [NoLevel + 1][samplerBuckets]samplerCounter
+}
+
+type samplerCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+	dropped atomic.Uint64
+}
+
+// NewSampler returns a Sampler which logs the first lines in every tick, and then every thereafter-th line.
+// If thereafter is 0, all lines after the first lines are dropped.
+func NewSampler(first, thereafter int, tick time.Duration) *Sampler {
+	return &Sampler{
+		first:      uint64(first),
+		thereafter: uint64(thereafter),
+		tick:       tick.Nanoseconds(),
+	}
+}
+
+// Dropped returns the total number of lines dropped by the sampler.
+func (s *Sampler) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Sample sets the sampler of the logger. Children created after this call inherit the sampler.
+// Pass nil to disable sampling.
+func (l *Logger) Sample(s *Sampler) *Logger {
+	l.sampler = s
+	return l
+}
+
+// sample returns whether the line should be logged, and the number of lines dropped since the last logged one.
+func (s *Sampler) sample(lvl Level, msg string, now time.Time) (bool, uint64) {
+	if lvl < TraceLevel || lvl > NoLevel {
+		lvl = NoLevel
+	}
+	c := &s.counters[lvl][fnv32a(msg)%samplerBuckets]
+
+	n := c.inc(now.UnixNano(), s.tick)
+	if n <= s.first || (s.thereafter > 0 && (n-s.first)%s.thereafter == 0) {
+		return true, c.dropped.Swap(0)
+	}
+
+	c.dropped.Add(1)
+	s.dropped.Add(1)
+	return false, 0
+}
+
+func (c *samplerCounter) inc(now int64, tick int64) uint64 {
+	resetAt := c.resetAt.Load()
+	if resetAt > now {
+		return c.count.Add(1)
+	}
+
+	c.count.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, now+tick) {
+		// Another goroutine has reset the counter.
+		return c.count.Add(1)
+	}
+	return 1
+}
+
+func fnv32a(s string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	hash := uint32(offset32)
+	for i := 0; i < len(s); i++ {
+		hash ^= uint32(s[i])
+		hash *= prime32
+	}
+	return hash
+}
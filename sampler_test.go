@@ -0,0 +1,58 @@
+package gclog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testClock struct{ t time.Time }
+
+func (c *testClock) Now() time.Time { return c.t }
+
+func TestSampler(t *testing.T) {
+	var b bytes.Buffer
+	clock := &testClock{t: time.Unix(1000, 0)}
+	s := NewSampler(2, 3, time.Second)
+	l := NewWithEncoder(&b, JsonEncoder{Time: TimeConfig{Disabled: true}}).SetClock(clock).Sample(s)
+
+	for i := 1; i <= 10; i++ {
+		l.Info().Int("n", i).Msg("a")
+	}
+	l.Info().Int("n", 1).Msg("b") // Another message has its own counter.
+	l.Warn().Int("n", 1).Msg("a") // So does another level.
+	// The counters are reset once the tick has passed. The dropped count is kept until the next logged line.
+	clock.t = clock.t.Add(time.Second)
+	l.Info().Int("n", 11).Msg("a")
+
+	want := []string{
+		// The first 2 lines, and then every 3rd line.
+		`{"level":"info", "n":1, "msg":"a"}`,
+		`{"level":"info", "n":2, "msg":"a"}`,
+		`{"level":"info", "n":5, "dropped":2, "msg":"a"}`,
+		`{"level":"info", "n":8, "dropped":2, "msg":"a"}`,
+		`{"level":"info", "n":1, "msg":"b"}`,
+		`{"level":"warn", "n":1, "msg":"a"}`,
+		`{"level":"info", "n":11, "dropped":2, "msg":"a"}`,
+	}
+	if got := strings.TrimSuffix(b.String(), "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+	if n := s.Dropped(); n != 6 {
+		t.Errorf("Dropped() = %d, want 6", n)
+	}
+}
+
+func TestSamplerDropsAllAfterFirst(t *testing.T) {
+	s := NewSampler(1, 0, time.Second)
+	now := time.Unix(1000, 0)
+	for i, want := range []bool{true, false, false} {
+		if ok, _ := s.sample(InfoLevel, "a", now); ok != want {
+			t.Errorf("line %d: sampled %v, want %v", i+1, ok, want)
+		}
+	}
+	if ok, dropped := s.sample(InfoLevel, "a", now.Add(time.Second)); !ok || dropped != 2 {
+		t.Errorf("after the tick: sampled %v, dropped %d, want true, 2", ok, dropped)
+	}
+}
@@ -0,0 +1,26 @@
+package gclog
+
+// Suffix of the key of the field written by the slice methods if the slice is longer than SliceConfig.MaxLen. Eg: "ids_more".
+const sliceMoreSuffix = "_more"
+
+// SliceConfig sets how the slice methods (Eg: Line.Ints) write a slice.
+type SliceConfig struct {
+	// MaxLen is the max number of values written. The number of the rest is
+	// written in the field key+"_more". Eg: ids=[1, 2], ids_more=98. 0 means no limit.
+	MaxLen int
+	// NilAsNull writes a nil slice as null. Otherwise, as an empty array.
+	NilAsNull bool
+}
+
+// SetSlices sets the slice config of the logger. Children created after this call inherit it.
+func (l *Logger) SetSlices(c SliceConfig) *Logger {
+	l.slices = c
+	return l
+}
+
+func (l *Line) sliceConfig() SliceConfig {
+	if l.log == nil {
+		return SliceConfig{}
+	}
+	return l.log.slices
+}
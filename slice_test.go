@@ -0,0 +1,58 @@
+package gclog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSlices(t *testing.T) {
+	noTime := TimeConfig{Disabled: true}
+	tests := []struct {
+		name string
+		enc  Encoder
+		c    SliceConfig
+		want string
+	}{
+		{"json", JsonEncoder{Time: noTime}, SliceConfig{},
+			`{"level":"info", "e":[], "n":[], "l":[1, 2, 3, 4], "s":["a b", "c", "d"], "msg":"hi"}`},
+		{"json config", JsonEncoder{Time: noTime}, SliceConfig{MaxLen: 2, NilAsNull: true},
+			`{"level":"info", "e":[], "n":null, "l":[1, 2], "l_more":2, "s":["a b", "c"], "s_more":1, "msg":"hi"}`},
+		{"text", TextEncoder{Time: noTime}, SliceConfig{},
+			`level=info, e=[], n=[], l=[1, 2, 3, 4], s=["a b", "c", "d"], msg="hi"`},
+		{"text config", TextEncoder{Time: noTime}, SliceConfig{MaxLen: 2, NilAsNull: true},
+			`level=info, e=[], n=nil, l=[1, 2], l_more=2, s=["a b", "c"], s_more=1, msg="hi"`},
+		{"logfmt", LogfmtEncoder{Time: noTime}, SliceConfig{},
+			`level=info e="[]" n="[]" l="[1, 2, 3, 4]" s="[a b, c, d]" msg=hi`},
+		{"logfmt config", LogfmtEncoder{Time: noTime}, SliceConfig{MaxLen: 2, NilAsNull: true},
+			`level=info e="[]" n=nil l="[1, 2]" l_more=2 s="[a b, c]" s_more=1 msg=hi`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			l := NewWithEncoder(&b, tt.enc).SetSlices(tt.c)
+			// The config is inherited by children.
+			child := l.With().Str("c", "1").Logger()
+			l.Info().Ints("e", []int{}).Ints("n", nil).Ints("l", []int{1, 2, 3, 4}).Strs("s", []string{"a b", "c", "d"}).Msg("hi")
+			child.Info().Ints("l", []int{1, 2, 3, 4}).Msg("hi")
+
+			lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+			if lines[0] != tt.want {
+				t.Errorf("got  %s\nwant %s", lines[0], tt.want)
+			}
+			if capped := strings.Contains(lines[1], "l_more"); capped != (tt.c.MaxLen > 0) {
+				t.Errorf("child line: %s", lines[1])
+			}
+		})
+	}
+}
+
+func TestSlicesAreNotCappedByDefault(t *testing.T) {
+	var b bytes.Buffer
+	l := NewWithEncoder(&b, JsonEncoder{Time: TimeConfig{Disabled: true}})
+	l.Info().Ints("l", make([]int, 1000)).Msg("hi")
+
+	if n := strings.Count(b.String(), "0"); n != 1000 {
+		t.Errorf("got %d values, want 1000", n)
+	}
+}
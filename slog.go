@@ -0,0 +1,205 @@
+package gclog
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SlogHandler is a slog.Handler which writes the records using the gclog Logger.
+// Groups are written as nested objects, like Line.Dict. Ie: in JSON, as
+// objects. In text and logfmt, as dotted keys. Eg: "req.method".
+type SlogHandler struct {
+	log    *Logger
+	groups []slogGroup // Open groups. The attrs added before the first group are in the context of log.
+}
+
+// slogGroup is a group opened by WithGroup, with the attrs added after it.
+type slogGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+func NewSlogHandler(l *Logger) *SlogHandler {
+	return &SlogHandler{log: l}
+}
+
+// Slog returns a slog.Logger which writes using the logger.
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(NewSlogHandler(l))
+}
+
+func (h *SlogHandler) Enabled(_ context.Context, lvl slog.Level) bool {
+	return h.log.Enabled(levelFromSlog(lvl))
+}
+
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	line := h.log.WithLevel(levelFromSlog(r.Level))
+	if line == nil {
+		return nil
+	}
+
+	// The time of the record is written, instead of the time of the clock. A zero time is not written.
+	line.timestamp = r.Time
+	line.hasTime = true
+	appendSlogGroups(line, h.groups, &r)
+	line.Msg(r.Message)
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	if len(h.groups) == 0 {
+		context := h.log.With()
+		for _, a := range attrs {
+			appendSlogAttr(context, a)
+		}
+		return &SlogHandler{log: context.Logger()}
+	}
+
+	// The attrs are in the last open group. They are written with every record, in the objects of the groups.
+	groups := append([]slogGroup(nil), h.groups...)
+	last := &groups[len(groups)-1]
+	last.attrs = append(append([]slog.Attr(nil), last.attrs...), attrs...)
+	return &SlogHandler{log: h.log, groups: groups}
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	groups := append([]slogGroup(nil), h.groups...)
+	groups = append(groups, slogGroup{name: name})
+	return &SlogHandler{log: h.log, groups: groups}
+}
+
+// appendSlogGroups writes the open groups as nested objects, and the attrs of the record in the innermost one.
+// Groups without any attrs are not written.
+func appendSlogGroups(line *Line, groups []slogGroup, r *slog.Record) {
+	if len(groups) == 0 {
+		r.Attrs(func(a slog.Attr) bool {
+			appendSlogAttr(line, a)
+			return true
+		})
+		return
+	}
+
+	if !slogGroupsHaveAttrs(groups, r) {
+		return
+	}
+	g := groups[0]
+	line.Dict(g.name, func(d *Line) {
+		for _, a := range g.attrs {
+			appendSlogAttr(d, a)
+		}
+		appendSlogGroups(d, groups[1:], r)
+	})
+}
+
+func slogGroupsHaveAttrs(groups []slogGroup, r *slog.Record) bool {
+	for _, g := range groups {
+		for _, a := range g.attrs {
+			if !slogAttrIsEmpty(a) {
+				return true
+			}
+		}
+	}
+	has := false
+	r.Attrs(func(a slog.Attr) bool {
+		has = !slogAttrIsEmpty(a)
+		return !has
+	})
+	return has
+}
+
+// slogAttrIsEmpty returns true if the attr is not written. Ie: an empty attr or a group without attrs.
+func slogAttrIsEmpty(a slog.Attr) bool {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return true
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		return false
+	}
+	for _, ga := range a.Value.Group() {
+		if !slogAttrIsEmpty(ga) {
+			return false
+		}
+	}
+	return true
+}
+
+func levelFromSlog(lvl slog.Level) Level {
+	switch {
+	case lvl < slog.LevelDebug:
+		return TraceLevel
+	case lvl < slog.LevelInfo:
+		return DebugLevel
+	case lvl < slog.LevelWarn:
+		return InfoLevel
+	case lvl < slog.LevelError:
+		return WarnLevel
+	default:
+		// Never FatalLevel. Because, it exits the process.
+		return ErrorLevel
+	}
+}
+
+func appendSlogAttr(line *Line, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if slogAttrIsEmpty(a) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if a.Key == "" {
+			// Inline the attrs of a group without a key.
+			for _, ga := range attrs {
+				appendSlogAttr(line, ga)
+			}
+			return
+		}
+		line.Dict(a.Key, func(d *Line) {
+			for _, ga := range attrs {
+				appendSlogAttr(d, ga)
+			}
+		})
+		return
+	}
+
+	key := a.Key
+
+	v := a.Value
+	switch v.Kind() {
+	case slog.KindString:
+		line.Str(key, v.String())
+	case slog.KindInt64:
+		line.Int64(key, v.Int64())
+	case slog.KindUint64:
+		line.Uint64(key, v.Uint64())
+	case slog.KindFloat64:
+		line.Float64(key, v.Float64())
+	case slog.KindBool:
+		line.Bool(key, v.Bool())
+	case slog.KindDuration:
+		line.Dur(key, v.Duration())
+	case slog.KindTime:
+		line.Time(key, v.Time())
+	default:
+		switch val := v.Any().(type) {
+		case error:
+			line.Str(key, val.Error())
+		case time.Time:
+			line.Time(key, val)
+		case []byte:
+			line.Bytes(key, val)
+		default:
+			line.Interface(key, val)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package gclog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"testing/slogtest"
+	"time"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var b bytes.Buffer
+	h := NewSlogHandler(New(&b, true))
+
+	results := func() []map[string]any {
+		var ms []map[string]any
+		for _, line := range bytes.Split(b.Bytes(), []byte{'\n'}) {
+			if len(line) == 0 {
+				continue
+			}
+			var m map[string]any
+			if err := json.Unmarshal(line, &m); err != nil {
+				t.Fatalf("invalid JSON line %q: %v", line, err)
+			}
+			ms = append(ms, m)
+		}
+		return ms
+	}
+	if err := slogtest.TestHandler(h, results); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSlogHandlerRecordTime(t *testing.T) {
+	var b bytes.Buffer
+	h := NewSlogHandler(NewWithEncoder(&b, TextEncoder{Time: TimeConfig{Format: time.RFC3339, UTC: true}}))
+
+	r := slog.NewRecord(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), slog.LevelInfo, "hi", 0)
+	r.AddAttrs(slog.Group("req", slog.String("method", "GET")))
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	want := `2020-01-02T03:04:05Z level=info, req.method="GET", msg="hi"` + "\n"
+	if b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+}
@@ -0,0 +1,226 @@
+package gclog
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const stackKey = "stack"
+const panicKey = "panic"
+
+// Number of PCs captured for a stack at first. It is doubled until the whole stack fits.
+const stackDepth = 64
+
+// StackFrame is a frame of a stack trace.
+type StackFrame struct {
+	Function string
+	File     string
+	Line     int
+	// Repeat is the number of times the frame is repeated consecutively. Eg: in a recursion. 1 if not repeated.
+	Repeat int
+}
+
+// StackTracer is implemented by errors which carry the stack of where they were created.
+//
+// Errors of github.com/pkg/errors carry a stack too. But, their StackTrace
+// returns errors.StackTrace. Wrap them with an adapter to write their stack. Eg:
+//
+//	type pkgStackErr struct{ error }
+//
+//	func (e pkgStackErr) Unwrap() error { return e.error }
+//
+//	func (e pkgStackErr) StackTrace() []uintptr {
+//		var st interface{ StackTrace() errors.StackTrace }
+//		if !errors.As(e.error, &st) {
+//			return nil
+//		}
+//		pcs := make([]uintptr, 0, len(st.StackTrace()))
+//		for _, f := range st.StackTrace() {
+//			pcs = append(pcs, uintptr(f))
+//		}
+//		return pcs
+//	}
+//
+//	log.Error().Stack().Err(pkgStackErr{err}).Msg("failed")
+type StackTracer interface {
+	StackTrace() []uintptr
+}
+
+// SetErrStack makes Line.Err write the stack trace of every error. Children created after this call inherit it.
+func (l *Logger) SetErrStack(on bool) *Logger {
+	l.errStack = on
+	return l
+}
+
+// Stack makes the next Line.Err write the stack trace of the error. If the
+// error carries a stack (see StackTracer), that stack is written. Otherwise,
+// the stack of the Err call is written.
+func (l *Line) Stack() *Line {
+	if l == nil {
+		return l
+	}
+
+	l.stack = true
+	return l
+}
+
+// LogPanic logs the panic, with its stack, at error level. Then, it panics again with the same value. Use it with defer:
+//
+//	defer log.LogPanic()
+func (l *Logger) LogPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	if line := l.WithLevel(ErrorLevel); line != nil {
+		pcs := callers(2) // 2 -> Skip runtime.Callers and LogPanic.
+		line.Str(panicKey, fmt.Sprint(r))
+		line.appendStackField(stackFrames(pcs, true))
+		line.Send()
+	}
+	l.Flush()
+	panic(r)
+}
+
+func (l *Line) appendStack(err error) {
+	pcs := errStack(err)
+	skipInternal := false
+	if pcs == nil {
+		pcs = callers(3) // 3 -> Skip runtime.Callers, appendStack and Err.
+		skipInternal = true
+	}
+
+	l.appendStackField(stackFrames(pcs, skipInternal))
+}
+
+// pendingStack is a stack field which is written after the other fields of the line.
+type pendingStack struct {
+	key    string
+	frames []StackFrame
+}
+
+// appendStackField writes the stack field. A stack may be written as a block
+// of lines. Eg: by TextEncoder. So, if the line is flattened (Ie: the encoder
+// has no nested objects, and it is not in an array), the field is written after
+// all the other fields, including the message. Fields of the context of a
+// logger are written before the fields of every line. So, they are not delayed.
+func (l *Line) appendStackField(frames []StackFrame) {
+	if _, nests := l.enc.(ObjectEncoder); !nests && l.arrayDepth == 0 && l.log != nil && l.log.context != l {
+		l.stacks = append(l.stacks, pendingStack{key: l.keyPrefix + stackKey, frames: frames})
+		return
+	}
+
+	l.appendKey(stackKey)
+	l.appendStackFrames(frames)
+}
+
+func (l *Line) appendPendingStacks() {
+	for _, s := range l.stacks {
+		l.appendKey(s.key)
+		l.appendStackFrames(s.frames)
+	}
+}
+
+// callers returns the PCs of the whole stack. skip is same as runtime.Callers, from the caller of callers.
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, stackDepth)
+	for {
+		// +1 -> Skip callers.
+		if n := runtime.Callers(skip+1, pcs); n < len(pcs) {
+			return pcs[:n]
+		}
+		pcs = make([]uintptr, len(pcs)*2)
+	}
+}
+
+// appendTextStack writes the frames as an indented block. Eg:
+//
+//	main.fib
+//	    /src/main.go:12 (x40)
+//	main.main
+//	    /src/main.go:20
+func appendTextStack(dst []byte, frames []StackFrame) []byte {
+	for _, f := range frames {
+		dst = append(dst, "\n    "...)
+		dst = append(dst, f.Function...)
+		dst = append(dst, "\n        "...)
+		dst = append(dst, f.File...)
+		dst = append(dst, ':')
+		dst = strconv.AppendInt(dst, int64(f.Line), 10)
+		if f.Repeat > 1 {
+			dst = append(dst, " (x"...)
+			dst = strconv.AppendInt(dst, int64(f.Repeat), 10)
+			dst = append(dst, ')')
+		}
+	}
+	return dst
+}
+
+func (l *Line) appendStackFrames(frames []StackFrame) {
+	se, ok := l.enc.(StackEncoder)
+	if !ok {
+		// The block starts with a new line, to write it below the key in text. It is not needed in a string.
+		block := appendTextStack(nil, frames)
+		if len(block) > 0 {
+			block = block[1:]
+		}
+		l.appendStr(string(block))
+		return
+	}
+
+	if l.canColorize {
+		l.buff = append(l.buff, styleValStart...)
+	}
+	l.buff = se.AppendStack(l.buff, frames)
+	if l.canColorize {
+		l.buff = append(l.buff, styleValEnd...)
+	}
+}
+
+// stackFrames returns the frames of the pcs. Frames of gclog (and of the panic) at the top are
+// skipped if skipInternal is true. Consecutive repeated frames are merged.
+func stackFrames(pcs []uintptr, skipInternal bool) []StackFrame {
+	frames := make([]StackFrame, 0, len(pcs))
+	for _, pc := range pcs {
+		for _, f := range callerFrames(pc) {
+			if skipInternal && len(frames) == 0 && (f.internal || strings.HasPrefix(f.frame.Function, "runtime.")) {
+				continue
+			}
+
+			if n := len(frames); n > 0 {
+				last := &frames[n-1]
+				if last.Function == f.frame.Function && last.File == f.frame.File && last.Line == f.frame.Line {
+					last.Repeat++
+					continue
+				}
+			}
+			frame := f.frame
+			frame.Repeat = 1
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
+
+// errStack returns the stack carried by err, or by the errors wrapped by it (See walkErrChain).
+// The innermost stack is returned, as it is the closest to where the error was created.
+func errStack(err error) []uintptr {
+	pcs := stackOf(err)
+	walkErrChain(err, func(e error) bool {
+		if st := stackOf(e); st != nil {
+			pcs = st
+		}
+		return true
+	})
+	return pcs
+}
+
+func stackOf(err error) []uintptr {
+	if st, ok := err.(StackTracer); ok {
+		return st.StackTrace()
+	}
+	return nil
+}
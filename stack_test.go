@@ -0,0 +1,99 @@
+package gclog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type stackFrameJSON struct {
+	Function string `json:"function"`
+	Repeat   int    `json:"repeat"`
+}
+
+func stackOfLine(t *testing.T, line []byte) []stackFrameJSON {
+	t.Helper()
+	var m struct {
+		Stack []stackFrameJSON `json:"stack"`
+	}
+	if err := json.Unmarshal(line, &m); err != nil {
+		t.Fatalf("invalid JSON %q: %v", line, err)
+	}
+	return m.Stack
+}
+
+func recurse(l *Logger, n int) {
+	if n == 0 {
+		l.Info().Stack().Err(errors.New("deep")).Send()
+		return
+	}
+	recurse(l, n-1)
+}
+
+func TestStackDeepRecursion(t *testing.T) {
+	var b bytes.Buffer
+	recurse(New(&b, true), 200)
+
+	frames := stackOfLine(t, b.Bytes())
+	if len(frames) < 3 {
+		t.Fatalf("got %d frames, want at least 3: %+v", len(frames), frames)
+	}
+	if !strings.HasSuffix(frames[0].Function, ".recurse") {
+		t.Errorf("first frame = %s, want recurse", frames[0].Function)
+	}
+	if !strings.HasSuffix(frames[1].Function, ".recurse") || frames[1].Repeat != 200 {
+		t.Errorf("second frame = %+v, want recurse repeated 200 times", frames[1])
+	}
+	if !strings.HasSuffix(frames[2].Function, ".TestStackDeepRecursion") {
+		t.Errorf("third frame = %s, want the test", frames[2].Function)
+	}
+}
+
+type stackErr struct{ pcs []uintptr }
+
+func (e stackErr) Error() string         { return "with stack" }
+func (e stackErr) StackTrace() []uintptr { return e.pcs }
+
+func newStackErr() error {
+	return stackErr{pcs: callers(1)}
+}
+
+func TestStackOfError(t *testing.T) {
+	var b bytes.Buffer
+	l := New(&b, true)
+	l.Info().Stack().Err(errors.Join(errors.New("other"), newStackErr())).Send()
+
+	frames := stackOfLine(t, b.Bytes())
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, ".newStackErr") {
+		t.Errorf("got %+v, want the stack of the error", frames)
+	}
+}
+
+func TestStackIsResetAfterErr(t *testing.T) {
+	var b bytes.Buffer
+	l := New(&b, true)
+	l.Info().Dict("d", func(d *Line) {
+		d.Stack().Err(errors.New("inner"))
+	}).Err(errors.New("outer")).Send()
+
+	if n := strings.Count(b.String(), `"stack":`); n != 1 {
+		t.Errorf("got %d stacks, want 1: %s", n, b.String())
+	}
+}
+
+func TestTextStackIsWrittenLast(t *testing.T) {
+	var b bytes.Buffer
+	l := NewWithEncoder(&b, TextEncoder{Time: TimeConfig{Disabled: true}})
+	l.Info().Stack().Err(errors.New("x")).Str("k", "v").Msg("stack text")
+
+	out := b.String()
+	head, block, ok := strings.Cut(out, "\n")
+	if !ok || head != `level=info, err="x", k="v", msg="stack text", stack=` {
+		t.Fatalf("got %q", out)
+	}
+	if !strings.Contains(block, ".TestTextStackIsWrittenLast\n") || strings.Contains(block, "=") {
+		t.Errorf("got the block %q", block)
+	}
+}
@@ -0,0 +1,44 @@
+package gclog
+
+import (
+	"bytes"
+	"log"
+)
+
+// stdLogWriter writes every line of the standard log package as a gclog line.
+type stdLogWriter struct {
+	log   *Logger
+	level Level
+}
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	// log.Logger always adds a "\n" at the end.
+	p = bytes.TrimSuffix(p, []byte{'\n'})
+	w.log.WithLevel(w.level).Msg(string(p))
+	return n, nil
+}
+
+// StdLogger returns a *log.Logger which writes using the logger with the given level.
+// To add context fields, use a child. Eg: l.With().Str("lib", "x").Logger().StdLogger(InfoLevel).
+func (l *Logger) StdLogger(lvl Level) *log.Logger {
+	return log.New(&stdLogWriter{log: l, level: lvl}, "", 0)
+}
+
+// RedirectStdLog makes the global logger of the standard log package write
+// using the logger with the given level. It returns a function to undo it.
+func (l *Logger) RedirectStdLog(lvl Level) func() {
+	flags := log.Flags()
+	prefix := log.Prefix()
+	out := log.Writer()
+
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(&stdLogWriter{log: l, level: lvl})
+
+	return func() {
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		log.SetOutput(out)
+	}
+}
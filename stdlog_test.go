@@ -0,0 +1,41 @@
+package gclog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStdLoggerEscapesMessage(t *testing.T) {
+	const msg = "line1\nline2 \"q\" \\ \r"
+
+	t.Run("text", func(t *testing.T) {
+		var b bytes.Buffer
+		l := NewWithEncoder(&b, TextEncoder{Time: TimeConfig{Disabled: true}})
+		l.StdLogger(InfoLevel).Print(msg)
+
+		want := `level=info, msg="line1\nline2 \"q\" \\ \r"` + "\n"
+		if b.String() != want {
+			t.Errorf("got %q, want %q", b.String(), want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var b bytes.Buffer
+		l := New(&b, true)
+		l.StdLogger(InfoLevel).Print(msg)
+
+		out := b.String()
+		if strings.Count(out, "\n") != 1 {
+			t.Fatalf("got %d lines, want 1: %q", strings.Count(out, "\n"), out)
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(out), &m); err != nil {
+			t.Fatalf("invalid JSON %q: %v", out, err)
+		}
+		if m["msg"] != msg {
+			t.Errorf("msg = %q, want %q", m["msg"], msg)
+		}
+	})
+}
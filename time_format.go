@@ -0,0 +1,67 @@
+package gclog
+
+import (
+	"strconv"
+	"time"
+)
+
+// Formats of TimeConfig, other than the time layouts. They write the time as a number.
+const (
+	TimeFormatUnix      = "unix"
+	TimeFormatUnixMs    = "unixms"
+	TimeFormatUnixMicro = "unixmicro"
+	TimeFormatUnixNano  = "unixnano"
+)
+
+const timeKey = "time"
+
+// TimeConfig configures how an encoder writes the timestamp of the lines and the Line.Time values.
+// The zero value uses the defaults of the encoder.
+type TimeConfig struct {
+	// Key of the timestamp field. Default: "time". TextEncoder writes the timestamp without a key, unless Key is set.
+	Key string
+	// Format is one of TimeFormatUnix, TimeFormatUnixMs, TimeFormatUnixMicro,
+	// TimeFormatUnixNano, or a time layout. Eg: time.RFC3339Nano.
+	Format string
+	// UTC converts the time to UTC before writing it. Otherwise, it is written in its own location.
+	UTC bool
+	// Disabled removes the timestamp field from the lines. Eg: when running under systemd, which stamps lines itself.
+	// Line.Time values are still written.
+	Disabled bool
+}
+
+func (c TimeConfig) key() string {
+	if c.Key == "" {
+		return timeKey
+	}
+	return c.Key
+}
+
+func (c TimeConfig) format(defaultFormat string) string {
+	if c.Format == "" {
+		return defaultFormat
+	}
+	return c.Format
+}
+
+// appendUnix writes the time as a number, if the format is a unix format.
+func (c TimeConfig) appendUnix(dst []byte, t time.Time, format string) ([]byte, bool) {
+	switch format {
+	case TimeFormatUnix:
+		return strconv.AppendInt(dst, t.Unix(), 10), true
+	case TimeFormatUnixMs:
+		return strconv.AppendInt(dst, t.UnixMilli(), 10), true
+	case TimeFormatUnixMicro:
+		return strconv.AppendInt(dst, t.UnixMicro(), 10), true
+	case TimeFormatUnixNano:
+		return strconv.AppendInt(dst, t.UnixNano(), 10), true
+	}
+	return dst, false
+}
+
+func (c TimeConfig) appendLayout(dst []byte, t time.Time, layout string) []byte {
+	if c.UTC {
+		t = t.UTC()
+	}
+	return t.AppendFormat(dst, layout)
+}
@@ -0,0 +1,56 @@
+package gclog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAppendTimestamp(t *testing.T) {
+	at := time.Date(2020, 1, 2, 3, 4, 5, 123456789, time.FixedZone("IST", 5*3600+1800))
+	tests := []struct {
+		name string
+		enc  Encoder
+		want string
+	}{
+		{"json default", JsonEncoder{}, `"time":1577914445123456, `},
+		{"json key unix", JsonEncoder{Time: TimeConfig{Key: "ts", Format: TimeFormatUnix}}, `"ts":1577914445, `},
+		{"json unix ms", JsonEncoder{Time: TimeConfig{Format: TimeFormatUnixMs}}, `"time":1577914445123, `},
+		{"json unix micro", JsonEncoder{Time: TimeConfig{Format: TimeFormatUnixMicro}}, `"time":1577914445123456, `},
+		{"json unix nano", JsonEncoder{Time: TimeConfig{Format: TimeFormatUnixNano}}, `"time":1577914445123456789, `},
+		{"json layout", JsonEncoder{Time: TimeConfig{Format: time.RFC3339}}, `"time":"2020-01-02T03:04:05+05:30", `},
+		{"json layout utc", JsonEncoder{Time: TimeConfig{Format: time.RFC3339, UTC: true}}, `"time":"2020-01-01T21:34:05Z", `},
+		{"json unix utc", JsonEncoder{Time: TimeConfig{Format: TimeFormatUnix, UTC: true}}, `"time":1577914445, `},
+		{"json disabled", JsonEncoder{Time: TimeConfig{Disabled: true, Key: "ts"}}, ``},
+
+		{"text default", TextEncoder{}, `2020/01/02 03:04:05 `},
+		{"text utc", TextEncoder{Time: TimeConfig{UTC: true}}, `2020/01/01 21:34:05 `},
+		{"text key", TextEncoder{Time: TimeConfig{Key: "ts"}}, `ts=2020/01/02 03:04:05 `},
+		{"text unix ms", TextEncoder{Time: TimeConfig{Format: TimeFormatUnixMs}}, `1577914445123 `},
+		{"text layout", TextEncoder{Time: TimeConfig{Format: time.Kitchen}}, `3:04AM `},
+		{"text disabled", TextEncoder{Time: TimeConfig{Disabled: true}}, ``},
+
+		{"logfmt default", LogfmtEncoder{}, `time=2020-01-02T03:04:05.123456789+05:30 `},
+		{"logfmt utc", LogfmtEncoder{Time: TimeConfig{UTC: true}}, `time=2020-01-01T21:34:05.123456789Z `},
+		{"logfmt key unix micro", LogfmtEncoder{Time: TimeConfig{Key: "ts", Format: TimeFormatUnixMicro}}, `ts=1577914445123456 `},
+		{"logfmt layout with space", LogfmtEncoder{Time: TimeConfig{Format: "2006-01-02 15:04"}}, `time="2020-01-02 03:04" `},
+		{"logfmt disabled", LogfmtEncoder{Time: TimeConfig{Disabled: true}}, ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.enc.AppendTimestamp(nil, at)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisabledTimestampKeepsTimeValues(t *testing.T) {
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := TimeConfig{Disabled: true, Format: time.RFC3339}
+	for _, enc := range []Encoder{JsonEncoder{Time: c}, TextEncoder{Time: c}, LogfmtEncoder{Time: c}} {
+		got := string(enc.AppendTime(nil, at))
+		if got != `"2020-01-02T03:04:05Z"` && got != `2020-01-02T03:04:05Z` {
+			t.Errorf("%T: AppendTime = %s", enc, got)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package gclog
+
+import (
+	"context"
+	"errors"
+)
+
+// TraceContext holds the W3C trace-context IDs. See https://www.w3.org/TR/trace-context/.
+type TraceContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Flags   byte
+}
+
+// TraceFields holds the keys of the trace-context fields.
+type TraceFields struct {
+	TraceID    string
+	SpanID     string
+	TraceFlags string
+}
+
+// Presets of TraceFields. See Logger.SetTraceFields.
+var (
+	OTelTraceFields = TraceFields{TraceID: "trace_id", SpanID: "span_id", TraceFlags: "trace_flags"}
+	ECSTraceFields  = TraceFields{TraceID: "trace.id", SpanID: "span.id", TraceFlags: "trace.flags"}
+)
+
+// TraceExtractor reads the trace-context of a context. Eg: from the span of OpenTelemetry.
+type TraceExtractor func(ctx context.Context) (TraceContext, bool)
+
+// SetTraceFields sets the keys written by Line.TraceContext. Default: OTelTraceFields.
+// Children created after this call inherit them.
+func (l *Logger) SetTraceFields(f TraceFields) *Logger {
+	l.traceFields = f
+	return l
+}
+
+// SetTraceExtractor sets the function used by WithTraceFields to read the
+// trace-context of a context. Children created after this call inherit it.
+// Pass nil to read the trace-context stored by ContextWithTrace.
+func (l *Logger) SetTraceExtractor(fn TraceExtractor) *Logger {
+	l.traceExtractor = fn
+	return l
+}
+
+func traceFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceCtxKey{}).(TraceContext)
+	return tc, ok && tc.IsValid()
+}
+
+var errInvalidTraceparent = errors.New("gclog: invalid traceparent")
+
+type traceCtxKey struct{}
+
+// IsValid returns false if the trace ID or span ID is all zeros.
+func (tc TraceContext) IsValid() bool {
+	return tc.TraceID != [16]byte{} && tc.SpanID != [8]byte{}
+}
+
+// ParseTraceparent parses a traceparent header. Eg: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func ParseTraceparent(header string) (TraceContext, error) {
+	var tc TraceContext
+	// version "-" trace-id "-" parent-id "-" trace-flags
+	if len(header) < 55 || header[2] != '-' || header[35] != '-' || header[52] != '-' {
+		return tc, errInvalidTraceparent
+	}
+	// Version ff is invalid. Future versions may have more fields after the flags.
+	if header[:2] == "ff" || (header[:2] == "00" && len(header) != 55) || (len(header) > 55 && header[55] != '-') {
+		return tc, errInvalidTraceparent
+	}
+
+	var version [1]byte
+	var flags [1]byte
+	ok := decodeHex(version[:], header[:2]) &&
+		decodeHex(tc.TraceID[:], header[3:35]) &&
+		decodeHex(tc.SpanID[:], header[36:52]) &&
+		decodeHex(flags[:], header[53:55])
+	if !ok {
+		return tc, errInvalidTraceparent
+	}
+	tc.Flags = flags[0]
+	if !tc.IsValid() {
+		return tc, errInvalidTraceparent
+	}
+	return tc, nil
+}
+
+// ContextWithTrace returns a copy of ctx which carries the trace-context.
+func ContextWithTrace(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceCtxKey{}, tc)
+}
+
+// WithTraceFields stores a child of the logger carried by ctx, with the
+// trace-context fields of ctx. So, the IDs are formatted once per request,
+// and not for every line. The trace-context is read by the TraceExtractor of
+// the logger. It returns ctx as it is, if ctx has no trace-context.
+func WithTraceFields(ctx context.Context) context.Context {
+	log := FromContext(ctx)
+	extract := log.traceExtractor
+	if extract == nil {
+		extract = traceFromContext
+	}
+	tc, ok := extract(ctx)
+	if !ok {
+		return ctx
+	}
+	return NewContext(ctx, log.With().TraceContext(tc).Logger())
+}
+
+// WithTraceparent parses the traceparent header, and does WithTraceFields.
+// It returns ctx as it is, if the header is invalid. Eg: in a middleware,
+//
+//	ctx = gclog.WithTraceparent(ctx, r.Header.Get("traceparent"))
+func WithTraceparent(ctx context.Context, header string) context.Context {
+	tc, err := ParseTraceparent(header)
+	if err != nil {
+		return ctx
+	}
+	return WithTraceFields(ContextWithTrace(ctx, tc))
+}
+
+// TraceContext writes the trace ID, span ID and trace flags as hex strings, with the keys set by Logger.SetTraceFields.
+func (l *Line) TraceContext(tc TraceContext) *Line {
+	if l == nil {
+		return l
+	}
+
+	var traceID [32]byte
+	var spanID [16]byte
+	var flags [2]byte
+	encodeHex(traceID[:], tc.TraceID[:])
+	encodeHex(spanID[:], tc.SpanID[:])
+	encodeHex(flags[:], []byte{tc.Flags})
+
+	names := OTelTraceFields
+	if l.log != nil {
+		names = l.log.traceFields
+	}
+	l.appendKey(names.TraceID)
+	l.appendStr(string(traceID[:]))
+	l.appendKey(names.SpanID)
+	l.appendStr(string(spanID[:]))
+	l.appendKey(names.TraceFlags)
+	l.appendStr(string(flags[:]))
+	return l
+}
+
+// encodeHex writes src as lower case hex to dst. len(dst) must be 2*len(src).
+func encodeHex(dst []byte, src []byte) {
+	for i, b := range src {
+		dst[i*2] = hex[b>>4]
+		dst[i*2+1] = hex[b&0xF]
+	}
+}
+
+// decodeHex reads the lower case hex string src to dst. len(src) must be 2*len(dst).
+// The W3C trace-context allows only lower case hex.
+func decodeHex(dst []byte, src string) bool {
+	for i := range dst {
+		hi, ok1 := unhex(src[i*2])
+		lo, ok2 := unhex(src[i*2+1])
+		if !ok1 || !ok2 {
+			return false
+		}
+		dst[i] = hi<<4 | lo
+	}
+	return true
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	}
+	return 0, false
+}
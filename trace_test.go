@@ -0,0 +1,109 @@
+package gclog
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	valid := TraceContext{
+		TraceID: [16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  [8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		Flags:   0x01,
+	}
+	tests := []struct {
+		name   string
+		header string
+		want   TraceContext
+		ok     bool
+	}{
+		{"valid", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", valid, true},
+		{"not sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", TraceContext{TraceID: valid.TraceID, SpanID: valid.SpanID}, true},
+		{"uppercase trace id", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", TraceContext{}, false},
+		{"uppercase version", "0A-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", TraceContext{}, false},
+		{"all zero trace id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", TraceContext{}, false},
+		{"all zero span id", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", TraceContext{}, false},
+		{"version ff", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", TraceContext{}, false},
+		{"version 00 with more fields", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-xyz", TraceContext{}, false},
+		{"future version", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", valid, true},
+		{"future version with more fields", "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-what-the-future-holds", valid, true},
+		{"future version with bad delimiter", "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.what", TraceContext{}, false},
+		{"short", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1", TraceContext{}, false},
+		{"bad delimiter", "00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", TraceContext{}, false},
+		{"non hex", "00-4bf92f3577b34da6a3ce929d0e0e473g-00f067aa0ba902b7-01", TraceContext{}, false},
+		{"empty", "", TraceContext{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTraceparent(tt.header)
+			if (err == nil) != tt.ok {
+				t.Fatalf("ParseTraceparent(%q) error = %v, want ok = %v", tt.header, err, tt.ok)
+			}
+			if tt.ok && got != tt.want {
+				t.Errorf("ParseTraceparent(%q) = %+v, want %+v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceContextFields(t *testing.T) {
+	tc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := newLine(nil, false, JsonEncoder{})
+	defer linePool.Put(line)
+	line.TraceContext(tc)
+
+	want := `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736", "span_id":"00f067aa0ba902b7", "trace_flags":"01"`
+	if string(line.buff) != want {
+		t.Errorf("got %s, want %s", line.buff, want)
+	}
+}
+
+func TestTraceFieldsPerLogger(t *testing.T) {
+	tc, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	var b bytes.Buffer
+	l := NewWithEncoder(&b, JsonEncoder{Time: TimeConfig{Disabled: true}})
+	ecs := l.With().Logger().SetTraceFields(ECSTraceFields)
+	child := ecs.With().Logger() // Inherits the fields of ecs.
+	l.Info().TraceContext(tc).Send()
+	child.Info().TraceContext(tc).Send()
+
+	want := `{"level":"info", "trace_id":"4bf92f3577b34da6a3ce929d0e0e4736", "span_id":"00f067aa0ba902b7", "trace_flags":"01"}
+{"level":"info", "trace.id":"4bf92f3577b34da6a3ce929d0e0e4736", "span.id":"00f067aa0ba902b7", "trace.flags":"01"}
+`
+	if b.String() != want {
+		t.Errorf("got\n%swant\n%s", b.String(), want)
+	}
+}
+
+type otherTraceKey struct{}
+
+func TestWithTraceFields(t *testing.T) {
+	tc, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	var b bytes.Buffer
+	l := NewWithEncoder(&b, JsonEncoder{Time: TimeConfig{Disabled: true}})
+
+	ctx := WithTraceFields(ContextWithTrace(NewContext(context.Background(), l), tc))
+	FromContext(ctx).Info().Msg("hi")
+
+	// A logger with an extractor reads the trace-context from elsewhere. Eg: a tracing library.
+	other := l.With().Logger().SetTraceExtractor(func(ctx context.Context) (TraceContext, bool) {
+		tc, ok := ctx.Value(otherTraceKey{}).(TraceContext)
+		return tc, ok
+	})
+	ctx = NewContext(context.WithValue(context.Background(), otherTraceKey{}, tc), other)
+	FromContext(WithTraceFields(ctx)).Info().Msg("hi")
+	// No trace-context. So, the context is returned as it is.
+	if got := WithTraceFields(NewContext(context.Background(), other)); FromContext(got) != other {
+		t.Errorf("WithTraceFields changed the logger of a context without trace-context")
+	}
+
+	want := `{"level":"info", "trace_id":"4bf92f3577b34da6a3ce929d0e0e4736", "span_id":"00f067aa0ba902b7", "trace_flags":"01", "msg":"hi"}`
+	if got := strings.Split(strings.TrimSpace(b.String()), "\n"); len(got) != 2 || got[0] != want || got[1] != want {
+		t.Errorf("got\n%s", b.String())
+	}
+}
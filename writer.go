@@ -0,0 +1,190 @@
+package gclog
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+type Writer struct {
+	out   io.Writer
+	mut   sync.Mutex
+	level atomic.Int32 // Minimum level. Shared with children through the Writer.
+
+	namedLevelsMut sync.Mutex
+	namedLevels    map[string]*atomic.Int32
+
+	async   atomic.Pointer[asyncQueue]
+	dropped atomic.Uint64
+	closed  atomic.Bool
+
+	// True while onError runs. Failures of the lines written meanwhile are not reported.
+	// So, a handler which logs using the same logger does not call itself again.
+	handlingErr atomic.Bool
+
+	// Guarded by mut.
+	onError       func(err error)
+	fallback      io.Writer
+	fallbackAfter int
+	failures      int // Consecutive failures of out.
+}
+
+// Write writes the data to the output. In async mode, the data is copied
+// to the queue. So, the caller can reuse data once Write returns.
+func (w *Writer) Write(data []byte) {
+	if w.closed.Load() {
+		return
+	}
+	quiet := w.handlingErr.Load()
+	if q := w.async.Load(); q != nil {
+		q.push(w, data, quiet)
+		return
+	}
+
+	w.write(data, quiet)
+}
+
+// write writes data to the output, or to the fallback. If it fails, onError is called, unless quiet is true.
+func (w *Writer) write(data []byte, quiet bool) {
+	w.mut.Lock()
+	usingFallback := w.fallback != nil && w.failures >= w.fallbackAfter
+	var err error
+	if !usingFallback {
+		err = writeFull(w.out, data)
+		if err == nil {
+			w.failures = 0
+		} else {
+			w.failures++
+			if w.fallback != nil && w.failures >= w.fallbackAfter {
+				// The fallback takes over from this line. So, this line is not lost.
+				usingFallback = true
+			}
+		}
+	}
+	if usingFallback {
+		if fallbackErr := writeFull(w.fallback, data); fallbackErr != nil && err == nil {
+			err = fallbackErr
+		}
+	}
+	onError := w.onError
+	w.mut.Unlock()
+
+	if err != nil && onError != nil && !quiet {
+		w.reportError(onError, err)
+	}
+}
+
+// reportError calls onError, without holding the lock. So, the handler can
+// log using the same logger. Errors are not reported while the handler runs.
+// In async mode, the handler runs in its own goroutine. Because, a line logged
+// by it is written by the drain goroutine. Calling it from the drain goroutine
+// would block it, once the queue is full.
+func (w *Writer) reportError(onError func(err error), err error) {
+	if !w.handlingErr.CompareAndSwap(false, true) {
+		return
+	}
+	if w.async.Load() == nil {
+		defer w.handlingErr.Store(false)
+		onError(err)
+		return
+	}
+
+	go func() {
+		defer w.handlingErr.Store(false)
+		onError(err)
+	}()
+}
+
+// writeFull retries short writes until all of data is written or out returns an error.
+func writeFull(out io.Writer, data []byte) error {
+	for len(data) > 0 {
+		n, err := out.Write(data)
+		if err != nil {
+			return err
+		}
+		if n <= 0 {
+			return io.ErrShortWrite
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+// OnWriteError sets a function to be called when writing a line fails.
+// It is shared with the parent and children of the logger. The handler may log
+// using the same logger. But, failures of the lines written while it runs
+// (by any goroutine) are not reported. In async mode, it is called from a new goroutine.
+func (l *Logger) OnWriteError(fn func(err error)) *Logger {
+	l.w.mut.Lock()
+	defer l.w.mut.Unlock()
+
+	l.w.onError = fn
+	return l
+}
+
+// SetFallback sets a writer which takes over once the output has failed
+// afterFailures times in a row. Eg: l.SetFallback(os.Stderr, 3).
+// It is shared with the parent and children of the logger.
+func (l *Logger) SetFallback(fallback io.Writer, afterFailures int) *Logger {
+	l.w.mut.Lock()
+	defer l.w.mut.Unlock()
+
+	if afterFailures < 1 {
+		afterFailures = 1
+	}
+	l.w.fallback = fallback
+	l.w.fallbackAfter = afterFailures
+	return l
+}
+
+// Flush waits until the lines queued by the async Writer are written.
+func (l *Logger) Flush() {
+	if q := l.w.async.Load(); q != nil {
+		q.flush()
+	}
+}
+
+// Sync flushes the logger, and calls Sync on the output if it has one. Eg: *os.File.
+func (l *Logger) Sync() error {
+	l.Flush()
+
+	l.w.mut.Lock()
+	defer l.w.mut.Unlock()
+
+	err := syncOutput(l.w.out)
+	if l.w.fallback != nil {
+		if fallbackErr := syncOutput(l.w.fallback); err == nil {
+			err = fallbackErr
+		}
+	}
+	return err
+}
+
+// syncOutput calls Sync on w if it has one. Pipes, terminals and other
+// character devices (Eg: os.Stdout) can not be synced. That error is ignored.
+func syncOutput(w io.Writer) error {
+	s, ok := w.(interface{ Sync() error })
+	if !ok {
+		return nil
+	}
+	err := s.Sync()
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTSUP) {
+		return nil
+	}
+	return err
+}
+
+// Close syncs the logger. After that, the logger, its parent and children
+// (they share the Writer) don't write anything. It is safe to call while
+// other goroutines are logging. The output is not closed.
+func (l *Logger) Close() error {
+	if l.w.closed.Swap(true) {
+		return nil
+	}
+	if q := l.w.async.Load(); q != nil {
+		q.close()
+	}
+	return l.Sync()
+}
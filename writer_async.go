@@ -0,0 +1,160 @@
+package gclog
+
+import "sync"
+
+// OverflowPolicy decides what an async Writer does when its queue is full.
+type OverflowPolicy int
+
+const (
+	// Block waits until the queue has room for the line.
+	Block OverflowPolicy = iota
+	// DropNewest drops the line being written.
+	DropNewest
+	// DropOldest drops the oldest line in the queue to make room for the line being written.
+	DropOldest
+)
+
+var asyncBuffPool = &sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, buffSize)
+		return &b
+	},
+}
+
+// asyncItem is either a line to write, or a flush marker.
+type asyncItem struct {
+	line    *[]byte
+	quiet   bool          // Pushed while the error handler was running. See Writer.handlingErr.
+	flushed chan struct{} // Closed once all the lines queued before the marker are written.
+}
+
+type asyncQueue struct {
+	items    chan asyncItem
+	policy   OverflowPolicy
+	stop     chan struct{}
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+// Async makes the Writer of the logger (shared with its parent and children)
+// write lines from a background goroutine. Up to queueSize lines wait in the
+// queue. policy decides what happens when the queue is full.
+// Calling Async again on the same Writer has no effect.
+func (l *Logger) Async(queueSize int, policy OverflowPolicy) *Logger {
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	q := &asyncQueue{
+		items:   make(chan asyncItem, queueSize),
+		policy:  policy,
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if l.w.async.CompareAndSwap(nil, q) {
+		go q.drain(l.w)
+	}
+	return l
+}
+
+// DroppedLines returns the number of lines dropped by the async Writer because its queue was full.
+func (l *Logger) DroppedLines() uint64 {
+	return l.w.dropped.Load()
+}
+
+func (q *asyncQueue) push(w *Writer, data []byte, quiet bool) {
+	b := asyncBuffPool.Get().(*[]byte)
+	*b = append((*b)[:0], data...)
+	item := asyncItem{line: b, quiet: quiet}
+
+	switch q.policy {
+	case DropNewest:
+		select {
+		case q.items <- item:
+		default:
+			w.dropped.Add(1)
+			asyncBuffPool.Put(b)
+		}
+	case DropOldest:
+		q.pushDropOldest(w, item)
+	default:
+		select {
+		case q.items <- item:
+		case <-q.stop:
+			asyncBuffPool.Put(b)
+		}
+	}
+}
+
+// pushDropOldest pushes the item. If the queue is full, the oldest line is
+// dropped. Flush markers are never dropped. Because, the drain goroutine may
+// still be writing the lines queued before the marker. So, a marker taken out
+// of the queue is pushed again, after the item.
+func (q *asyncQueue) pushDropOldest(w *Writer, item asyncItem) {
+	var markers []asyncItem
+	for pushed := false; !pushed; {
+		select {
+		case q.items <- item:
+			pushed = true
+			continue
+		case <-q.stop:
+			if item.line != nil {
+				asyncBuffPool.Put(item.line)
+			}
+			return
+		default:
+		}
+		select {
+		case old := <-q.items:
+			if old.flushed != nil {
+				markers = append(markers, old)
+			} else {
+				w.dropped.Add(1)
+				asyncBuffPool.Put(old.line)
+			}
+		default:
+		}
+	}
+	for _, m := range markers {
+		q.pushDropOldest(w, m)
+	}
+}
+
+// flush waits until the lines queued before the call are written. It never drops lines.
+func (q *asyncQueue) flush() {
+	flushed := make(chan struct{})
+	select {
+	case q.items <- asyncItem{flushed: flushed}:
+	case <-q.stop:
+		return
+	}
+	select {
+	case <-flushed:
+	case <-q.stopped:
+	}
+}
+
+// close flushes the queue and stops the drain goroutine.
+func (q *asyncQueue) close() {
+	q.flush()
+	q.stopOnce.Do(func() {
+		close(q.stop)
+	})
+	<-q.stopped
+}
+
+func (q *asyncQueue) drain(w *Writer) {
+	defer close(q.stopped)
+	for {
+		select {
+		case item := <-q.items:
+			if item.flushed != nil {
+				close(item.flushed)
+				continue
+			}
+			w.write(*item.line, item.quiet)
+			asyncBuffPool.Put(item.line)
+		case <-q.stop:
+			return
+		}
+	}
+}
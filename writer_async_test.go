@@ -0,0 +1,151 @@
+package gclog
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gatedWriter blocks every Write until the gate is opened. started receives a value when a Write starts.
+type gatedWriter struct {
+	mut     sync.Mutex
+	buf     bytes.Buffer
+	started chan struct{}
+	gate    chan struct{}
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{started: make(chan struct{}, 100), gate: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.gate
+	w.mut.Lock()
+	defer w.mut.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gatedWriter) String() string {
+	w.mut.Lock()
+	defer w.mut.Unlock()
+	return w.buf.String()
+}
+
+// newAsyncTestLogger returns an async logger whose drain goroutine is blocked in the Write of the line n=1.
+func newAsyncTestLogger(t *testing.T, queueSize int, policy OverflowPolicy) (*Logger, *gatedWriter) {
+	w := newGatedWriter()
+	l := NewWithEncoder(w, JsonEncoder{Time: TimeConfig{Disabled: true}}).Async(queueSize, policy)
+	l.Info().Int("n", 1).Send()
+	select {
+	case <-w.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the first line is not written")
+	}
+	return l, w
+}
+
+func writtenNumbers(out string) string {
+	var nums []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		i := strings.Index(line, `"n":`)
+		nums = append(nums, strings.TrimSuffix(line[i+len(`"n":`):], "}"))
+	}
+	return strings.Join(nums, ",")
+}
+
+func TestAsyncBlock(t *testing.T) {
+	l, w := newAsyncTestLogger(t, 2, Block)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 2; i <= 5; i++ {
+			l.Info().Int("n", i).Send()
+		}
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("logging did not block on a full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(w.gate)
+	<-done
+	l.Flush()
+
+	if got := writtenNumbers(w.String()); got != "1,2,3,4,5" {
+		t.Errorf("written %s, want 1,2,3,4,5", got)
+	}
+	if n := l.DroppedLines(); n != 0 {
+		t.Errorf("DroppedLines() = %d, want 0", n)
+	}
+}
+
+func TestAsyncDropNewest(t *testing.T) {
+	l, w := newAsyncTestLogger(t, 2, DropNewest)
+	for i := 2; i <= 5; i++ {
+		l.Info().Int("n", i).Send()
+	}
+	close(w.gate)
+	l.Flush()
+
+	if got := writtenNumbers(w.String()); got != "1,2,3" {
+		t.Errorf("written %s, want 1,2,3", got)
+	}
+	if n := l.DroppedLines(); n != 2 {
+		t.Errorf("DroppedLines() = %d, want 2", n)
+	}
+}
+
+func TestAsyncDropOldest(t *testing.T) {
+	l, w := newAsyncTestLogger(t, 2, DropOldest)
+	for i := 2; i <= 5; i++ {
+		l.Info().Int("n", i).Send()
+	}
+	close(w.gate)
+	l.Flush()
+
+	if got := writtenNumbers(w.String()); got != "1,4,5" {
+		t.Errorf("written %s, want 1,4,5", got)
+	}
+	if n := l.DroppedLines(); n != 2 {
+		t.Errorf("DroppedLines() = %d, want 2", n)
+	}
+}
+
+func TestAsyncFlushWaitsForQueuedLines(t *testing.T) {
+	for _, policy := range []OverflowPolicy{Block, DropNewest, DropOldest} {
+		l, w := newAsyncTestLogger(t, 2, policy)
+		flushed := make(chan struct{})
+		go func() {
+			l.Flush()
+			close(flushed)
+		}()
+		time.Sleep(20 * time.Millisecond) // Let Flush queue its marker.
+
+		// In DropOldest, these lines push the marker out of the full queue. It must not end the Flush.
+		if policy != Block {
+			for i := 2; i <= 5; i++ {
+				l.Info().Int("n", i).Send()
+			}
+		}
+		select {
+		case <-flushed:
+			t.Fatalf("policy %d: Flush returned before the line n=1 is written", policy)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(w.gate)
+		select {
+		case <-flushed:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("policy %d: Flush did not return", policy)
+		}
+		if !strings.HasPrefix(w.String(), `{"level":"info", "n":1}`) {
+			t.Errorf("policy %d: written %q", policy, w.String())
+		}
+		l.Close()
+	}
+}
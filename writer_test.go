@@ -0,0 +1,128 @@
+package gclog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCloseOnPipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	go io.Copy(io.Discard, r)
+
+	l := New(w, true)
+	l.Info().Msg("hi")
+	if err := l.Sync(); err != nil {
+		t.Errorf("Sync() = %v, want nil", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("Close() = %v, want nil", err)
+	}
+}
+
+// shortWriter writes at most n bytes per call.
+type shortWriter struct {
+	bytes.Buffer
+	n int
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		p = p[:w.n]
+	}
+	return w.Buffer.Write(p)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestShortWritesAreRetried(t *testing.T) {
+	w := &shortWriter{n: 3}
+	l := New(w, true)
+	var errs []error
+	l.OnWriteError(func(err error) { errs = append(errs, err) })
+	l.Info().Str("k", "a long enough value").Msg("hi")
+
+	if !strings.Contains(w.String(), `"k":"a long enough value", "msg":"hi"}`+"\n") {
+		t.Errorf("got %q", w.String())
+	}
+	if len(errs) != 0 {
+		t.Errorf("got errors %v", errs)
+	}
+}
+
+func TestFallbackTakesOver(t *testing.T) {
+	var fallback bytes.Buffer
+	l := New(failingWriter{}, true).SetFallback(&fallback, 2)
+	errs := 0
+	l.OnWriteError(func(err error) { errs++ })
+	for i := 1; i <= 3; i++ {
+		l.Info().Int("n", i).Send()
+	}
+
+	// The first line is lost. The second one fails again, and the fallback takes over from it.
+	if got := fallback.String(); strings.Contains(got, `"n":1`) || !strings.Contains(got, `"n":2`) || !strings.Contains(got, `"n":3`) {
+		t.Errorf("fallback got %q", got)
+	}
+	if errs != 2 {
+		t.Errorf("got %d errors, want 2", errs)
+	}
+}
+
+func TestErrorHandlerCanLog(t *testing.T) {
+	l := New(failingWriter{}, true)
+	calls := 0
+	l.OnWriteError(func(err error) {
+		calls++
+		// Fails again. But, it is not reported. So, the handler is not called recursively.
+		l.Warn().Err(err).Msg("write failed")
+	})
+	for i := 0; i < 3; i++ {
+		l.Info().Msg("hi")
+	}
+
+	if calls != 3 {
+		t.Errorf("handler called %d times, want 3", calls)
+	}
+}
+
+func TestErrorHandlerCanLogAsync(t *testing.T) {
+	for _, policy := range []OverflowPolicy{Block, DropNewest, DropOldest} {
+		l := New(failingWriter{}, true).Async(1, policy)
+		var calls atomic.Int32
+		l.OnWriteError(func(err error) {
+			calls.Add(1)
+			l.Warn().Err(err).Msg("write failed")
+		})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 20; i++ {
+				l.Info().Msg("hi")
+			}
+			l.Close()
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("policy %d: logging from the error handler blocked the logger", policy)
+		}
+		if calls.Load() == 0 {
+			t.Errorf("policy %d: handler not called", policy)
+		}
+	}
+}